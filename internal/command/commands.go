@@ -4,8 +4,9 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"regexp"
 	"spellio/internal/spellcheck"
+	"spellio/levenshtein"
+	"spellio/phonetic"
 	"strings"
 
 	"github.com/urfave/cli/v2"
@@ -70,6 +71,9 @@ func correctCommand(wt *spellcheck.WordTrie, c *cli.Context) error {
 	if c.NArg() != 1 {
 		return fmt.Errorf("usage: spellio correct <word>")
 	}
+	if err := applyChannelModel(wt, c); err != nil {
+		return err
+	}
 
 	word := c.Args().Get(0)
 	corrections := wt.AutocorrectMultiple(word, 5)
@@ -81,7 +85,37 @@ func correctCommand(wt *spellcheck.WordTrie, c *cli.Context) error {
 
 	fmt.Println("Suggestions:")
 	for _, correction := range corrections {
-		fmt.Printf("- %s\n", correction.Word)
+		if c.Bool("edits") {
+			fmt.Printf("- %s  %s\n", correction.Word, formatEdits(wt.Align(word, correction.Word)))
+		} else {
+			fmt.Printf("- %s\n", correction.Word)
+		}
+	}
+	return nil
+}
+
+func SoundsLikeCommand(wt *spellcheck.WordTrie) func(*cli.Context) error {
+	return func(c *cli.Context) error { return soundsLikeCommand(wt, c) }
+}
+
+func soundsLikeCommand(wt *spellcheck.WordTrie, c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("usage: spellio soundslike <word>")
+	}
+
+	word := c.Args().Get(0)
+	matches := wt.SoundsLike(word)
+	if len(matches) == 0 {
+		fmt.Printf("No words sound like \"%s\".\n", word)
+		return nil
+	}
+	if limit := c.Int("limit"); limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	fmt.Printf("Words that sound like \"%s\" (%s):\n", word, phonetic.Metaphone(word))
+	for _, match := range matches {
+		fmt.Printf("- %s\n", match.Word)
 	}
 	return nil
 }
@@ -95,18 +129,26 @@ func sentenceCommand(wt *spellcheck.WordTrie, c *cli.Context) error {
 		return fmt.Errorf("usage: spellio sentence <sentence>")
 	}
 
+	if err := applyChannelModel(wt, c); err != nil {
+		return err
+	}
 	sentence := strings.Join(c.Args().Slice(), " ")
-	correctedSentence, correctionCount := processSentenceWithFeedback(wt, sentence)
+	correctedSentence, corrections := processSentenceWithFeedback(wt, sentence)
+	misspellings, realWords := countCorrections(corrections)
 
-	if correctionCount == 0 {
+	if len(corrections) == 0 {
 		fmt.Println("Your sentence is correct!")
 	} else {
-		if correctionCount == 1 {
+		if misspellings == 1 {
 			fmt.Println("Found 1 word in need of correction in your sentence:")
-		} else {
-			fmt.Printf("Found %d words in need of correction in your sentence:\n", correctionCount)
+		} else if misspellings > 1 {
+			fmt.Printf("Found %d words in need of correction in your sentence:\n", misspellings)
 		}
+		printRealWordCount(realWords)
 		fmt.Println(correctedSentence)
+		if c.Bool("edits") {
+			printCorrectionEdits(wt, corrections)
+		}
 	}
 	return nil
 }
@@ -146,26 +188,79 @@ func interactiveCommand(wt *spellcheck.WordTrie, c *cli.Context) error {
 	return nil
 }
 
-func processSentenceWithFeedback(wt *spellcheck.WordTrie, sentence string) (string, int) {
-	wordRegex := regexp.MustCompile(`\b[a-zA-Z]+(?:\"[a-zA-Z]+)?\b`)
-	correctionCount := 0
+func processSentenceWithFeedback(wt *spellcheck.WordTrie, sentence string) (string, []spellcheck.WordCorrection) {
+	corrections := wt.CorrectSentence(sentence)
 
-	result := wordRegex.ReplaceAllStringFunc(sentence, func(word string) string {
-		if wt.IsWord(word) {
-			return word
+	var sb strings.Builder
+	end := 0
+	for _, correction := range corrections {
+		if correction.Start < end {
+			continue
+		}
+		sb.WriteString(sentence[end:correction.Start])
+		if correction.Kind == spellcheck.RealWordError {
+			fmt.Fprintf(&sb, "{%s}", correction.Word)
+		} else if correction.Found {
+			fmt.Fprintf(&sb, "(%s)", correction.Word)
+		} else {
+			fmt.Fprintf(&sb, "[no suggestions](%s)", correction.Text)
 		}
+		end = correction.End
+	}
+	sb.WriteString(sentence[end:])
 
-		correction, found := wt.Autocorrect(word)
-		if found {
-			correctionCount++
-			return fmt.Sprintf("(%s)", correction.Word)
+	return sb.String(), corrections
+}
+
+// countCorrections counts misspelled words and real-word errors separately
+func countCorrections(corrections []spellcheck.WordCorrection) (misspellings, realWords int) {
+	for _, correction := range corrections {
+		if correction.Kind == spellcheck.RealWordError {
+			realWords++
+		} else {
+			misspellings++
 		}
+	}
+	return misspellings, realWords
+}
 
-		correctionCount++
-		return fmt.Sprintf("[no suggestions](%s)", word)
-	})
+func printRealWordCount(realWords int) {
+	if realWords == 1 {
+		fmt.Println("Found 1 word that may be confused with another:")
+	} else if realWords > 1 {
+		fmt.Printf("Found %d words that may be confused with others:\n", realWords)
+	}
+}
 
-	return result, correctionCount
+func printCorrectionEdits(wt *spellcheck.WordTrie, corrections []spellcheck.WordCorrection) {
+	for _, correction := range corrections {
+		switch {
+		case correction.Kind == spellcheck.RealWordError:
+			fmt.Printf("  %s -> %s  (%s)\n", correction.Text, correction.Word, correction.Kind)
+		case correction.Found:
+			fmt.Printf("  %s -> %s  %s\n", correction.Text, correction.Word,
+				formatEdits(wt.Align(correction.Text, correction.Word)))
+		}
+	}
+}
+
+// formatEdits renders an alignment as the corrected word with every change bracketed,
+// e.g. "rec[ie→ei]ve" or "hel[+l]o"
+func formatEdits(edits []levenshtein.Edit) string {
+	var sb strings.Builder
+	for _, e := range edits {
+		switch e.Op {
+		case levenshtein.Match:
+			sb.WriteString(e.To)
+		case levenshtein.Insert:
+			sb.WriteString("[+" + e.To + "]")
+		case levenshtein.Delete:
+			sb.WriteString("[-" + e.From + "]")
+		default:
+			sb.WriteString("[" + e.From + "→" + e.To + "]")
+		}
+	}
+	return sb.String()
 }
 
 func processInteractiveInput(wt *spellcheck.WordTrie, input string) error {
@@ -190,6 +285,10 @@ func processInteractiveInput(wt *spellcheck.WordTrie, input string) error {
 			fmt.Print("\033[2J\033[H")
 			return nil
 
+		case "cache":
+			printCacheStats(wt.CacheStats())
+			return nil
+
 		case "check", "ch":
 			if len(parts) != 2 {
 				return fmt.Errorf("usage: :check <word>")
@@ -213,16 +312,18 @@ func processInteractiveInput(wt *spellcheck.WordTrie, input string) error {
 				return fmt.Errorf("usage: :sentence <sentence>")
 			}
 			sentence := strings.Join(parts[1:], " ")
-			correctedSentence, correctionCount := processSentenceWithFeedback(wt, sentence)
+			correctedSentence, corrections := processSentenceWithFeedback(wt, sentence)
+			misspellings, realWords := countCorrections(corrections)
 
-			if correctionCount == 0 {
+			if len(corrections) == 0 {
 				fmt.Println("Your sentence is correct!")
 			} else {
-				if correctionCount == 1 {
+				if misspellings == 1 {
 					fmt.Println("Found 1 correction in your sentence:")
-				} else {
-					fmt.Printf("Found %d corrections in your sentence:\n", correctionCount)
+				} else if misspellings > 1 {
+					fmt.Printf("Found %d corrections in your sentence:\n", misspellings)
 				}
+				printRealWordCount(realWords)
 				fmt.Println(correctedSentence)
 			}
 			return nil
@@ -238,16 +339,18 @@ func processInteractiveInput(wt *spellcheck.WordTrie, input string) error {
 	}
 	// Multi-word input - treat as sentence
 	sentence := strings.Join(parts, " ")
-	correctedSentence, correctionCount := processSentenceWithFeedback(wt, sentence)
+	correctedSentence, corrections := processSentenceWithFeedback(wt, sentence)
+	misspellings, realWords := countCorrections(corrections)
 
-	if correctionCount == 0 {
+	if len(corrections) == 0 {
 		fmt.Println("Your sentence is correct!")
 	} else {
-		if correctionCount == 1 {
+		if misspellings == 1 {
 			fmt.Println("Found 1 correction in your sentence:")
-		} else {
-			fmt.Printf("Found %d corrections in your sentence:\n", correctionCount)
+		} else if misspellings > 1 {
+			fmt.Printf("Found %d corrections in your sentence:\n", misspellings)
 		}
+		printRealWordCount(realWords)
 		fmt.Println(correctedSentence)
 	}
 	return nil
@@ -259,6 +362,7 @@ func showInteractiveHelp() {
 	fmt.Println("  :complete <prefix> Get autocomplete suggestions for a prefix (alias: :c, :comp)")
 	fmt.Println("  :correct <word>    Get correct spelling suggestions for a word (alias: :cor)")
 	fmt.Println("  :sentence <text>   Check and correct all words in a sentence (alias: :sent)")
+	fmt.Println("  :cache             Show how often corrections came from the cache")
 	fmt.Println("  :clear             Clear the screen (alias: :cls)")
 	fmt.Println("  :help              Show this help message (alias: :h)")
 	fmt.Println("  :quit/:exit        Exit the program (alias: :q)")
@@ -268,6 +372,12 @@ func showInteractiveHelp() {
 	fmt.Println("  - Enter multiple words to check and correct the entire sentence")
 }
 
+func printCacheStats(stats spellcheck.CacheStats) {
+	fmt.Printf("Cache: %d of %d lookups cached\n", stats.Size, stats.Capacity)
+	fmt.Printf("  hits: %d, misses: %d (%.1f%% hit rate)\n", stats.Hits, stats.Misses, stats.HitRate()*100)
+	fmt.Printf("  evictions: %d\n", stats.Evictions)
+}
+
 func processCheck(wt *spellcheck.WordTrie, word string) error {
 	if wt.IsWord(word) {
 		fmt.Printf("\"%s\" is spelled correctly!\n", word)
@@ -0,0 +1,160 @@
+package command
+
+import (
+	"fmt"
+	"slices"
+	"spellio/internal/config"
+	"spellio/internal/spellcheck"
+	"spellio/levenshtein"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Configure prepares wt from the global flags before any command runs. Flags missing
+// from the command line are taken from the config file when it sets them.
+func Configure(wt *spellcheck.WordTrie) func(*cli.Context) error {
+	return func(c *cli.Context) error { return configure(wt, c) }
+}
+
+// dictionaryLoad is what loading the dictionaries took, for the stats command
+type dictionaryLoad struct {
+	dictionaries []spellcheck.DictionaryInfo
+	time         time.Duration
+	memory       int64
+}
+
+var loaded dictionaryLoad
+
+func configure(wt *spellcheck.WordTrie, c *cli.Context) error {
+	if err := applyConfigFile(c); err != nil {
+		return err
+	}
+	// Measuring the memory takes a garbage collection either side, so only stats pays for it
+	stats := isCommand(c, "stats")
+	var before int64
+	if stats {
+		before = heapInUse()
+	}
+	start := time.Now()
+	dictionaries, err := loadDictionaries(wt, c)
+	if err != nil {
+		return err
+	}
+	loaded = dictionaryLoad{dictionaries: dictionaries, time: time.Since(start)}
+	if stats {
+		// A DAWG is mapped rather than allocated, so the heap may even shrink by GC noise
+		loaded.memory = max(heapInUse()-before, 0)
+	}
+	warnSkipped(dictionaries)
+	if err = applySearchOptions(wt, c); err != nil {
+		return err
+	}
+	if path := c.String("lm"); path != "" {
+		lm, err := spellcheck.LoadLanguageModel(path)
+		if err != nil {
+			return err
+		}
+		wt.SetLanguageModel(lm)
+	}
+	if err = applyConfusionSets(wt, c); err != nil {
+		return err
+	}
+	backend, err := spellcheck.ParseBackend(c.String("backend"))
+	if err != nil || stats {
+		// stats builds every backend itself to measure it
+		return err
+	}
+	return wt.UseBackend(backend)
+}
+
+// isCommand tells whether the command line runs the command called name
+func isCommand(c *cli.Context, name string) bool {
+	cmd := c.App.Command(c.Args().First())
+	return cmd != nil && cmd.Name == name
+}
+
+func applyConfigFile(c *cli.Context) error {
+	path := c.String("config")
+	if path == "" {
+		path = config.DefaultPath()
+	}
+	values, err := config.Load(path)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range values {
+		if !slices.ContainsFunc(c.App.Flags, func(f cli.Flag) bool { return slices.Contains(f.Names(), key) }) {
+			return fmt.Errorf("%s: unknown setting %q", path, key)
+		}
+		if c.IsSet(key) {
+			continue
+		}
+		if err = c.Set(key, value); err != nil {
+			return fmt.Errorf("%s: %s: %w", path, key, err)
+		}
+	}
+	return nil
+}
+
+// applySearchOptions sets everything except the backend, which decides what gets built
+func applySearchOptions(wt *spellcheck.WordTrie, c *cli.Context) error {
+	layout, err := levenshtein.ParseLayout(c.String("layout"))
+	if err != nil {
+		return err
+	}
+
+	metric, err := spellcheck.ParseMetric(c.String("bk-metric"))
+	if err != nil {
+		return err
+	}
+	if metric.Name == spellcheck.KeyboardMetric.Name {
+		metric = spellcheck.KeyboardMetricFor(layout)
+	}
+	if err = wt.SetBKTreeMetric(metric); err != nil {
+		return err
+	}
+
+	distance, err := spellcheck.ParseDistance(c.String("distance"))
+	if err != nil {
+		return err
+	}
+	if err = wt.SetDistance(distance); err != nil {
+		return err
+	}
+
+	costs, err := spellcheck.ParseCostModel(c.String("costs"), layout)
+	if err != nil {
+		return err
+	}
+	wt.SetCostModel(costs)
+	wt.SetWorkers(c.Int("workers"))
+	wt.SetCacheSize(c.Int("cache-size"))
+
+	similarity, err := spellcheck.ParseSimilarity(c.String("similarity"))
+	if err != nil {
+		return err
+	}
+	return wt.SetSimilarity(similarity, c.Float64("similarity-weight"))
+}
+
+// applyConfusionSets turns on real-word error detection with the built-in confusion sets,
+// or those of --confusions instead, unless --real-words is off
+func applyConfusionSets(wt *spellcheck.WordTrie, c *cli.Context) error {
+	if !c.Bool("real-words") {
+		wt.SetConfusionSets(nil)
+		return nil
+	}
+	path := c.String("confusions")
+	if path == "" {
+		wt.SetConfusionSets(spellcheck.DefaultConfusionSets())
+		return nil
+	}
+	cs, err := spellcheck.LoadConfusionSets(path)
+	if err != nil {
+		return err
+	}
+	wt.SetConfusionSets(cs)
+	return nil
+}
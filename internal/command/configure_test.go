@@ -0,0 +1,74 @@
+package command
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"spellio/levenshtein"
+	"strings"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+)
+
+// configContext parses args against a layout and a config flag, with the config file
+// holding text
+func configContext(t *testing.T, text string, args ...string) *cli.Context {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	app := cli.NewApp()
+	app.Flags = []cli.Flag{
+		&cli.StringFlag{Name: "layout", Value: "qwerty"},
+		&cli.StringFlag{Name: "config"},
+	}
+	flags := flag.NewFlagSet("spellio", flag.ContinueOnError)
+	for _, f := range app.Flags {
+		if err := f.Apply(flags); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := flags.Parse(append([]string{"--config", path}, args...)); err != nil {
+		t.Fatal(err)
+	}
+	return cli.NewContext(app, flags, nil)
+}
+
+func TestApplyConfigFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		args       []string
+		wantLayout string
+		wantErr    string
+	}{
+		{name: "valid", text: "layout = dvorak\n", wantLayout: "dvorak"},
+		{name: "command line wins", text: "layout = dvorak\n", args: []string{"--layout", "colemak"}, wantLayout: "colemak"},
+		{name: "unknown layout", text: "layout = dvrak\n", wantErr: "unknown keyboard layout: dvrak"},
+		{name: "unknown setting", text: "keyboard = dvorak\n", wantErr: `unknown setting "keyboard"`},
+		{name: "malformed", text: "layout dvorak\n", wantErr: ":1: expected key = value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := configContext(t, tt.text, tt.args...)
+			err := applyConfigFile(c)
+			if err == nil {
+				_, err = levenshtein.ParseLayout(c.String("layout"))
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.String("layout"); got != tt.wantLayout {
+				t.Errorf("layout = %q, want %q", got, tt.wantLayout)
+			}
+		})
+	}
+}
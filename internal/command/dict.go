@@ -0,0 +1,69 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"spellio/internal/spellcheck"
+
+	"github.com/urfave/cli/v2"
+)
+
+func DictCompileCommand(wt *spellcheck.WordTrie) func(*cli.Context) error {
+	return func(c *cli.Context) error { return dictCompileCommand(wt, c) }
+}
+
+// dictCompileCommand writes the dictionaries loaded by the global options, and the index
+// of --backend if it builds one, to a snapshot that later runs load without rebuilding.
+// With --format dawg it writes a DAWG instead, which holds the words only. The file is
+// written next to the output and renamed over it, so that a DAWG being read from the
+// output stays intact.
+func dictCompileCommand(wt *spellcheck.WordTrie, c *cli.Context) error {
+	output := c.String("output")
+	var write func(io.Writer) error
+	switch format := c.String("format"); format {
+	case "snapshot":
+		write = wt.WriteSnapshot
+	case "dawg":
+		write = wt.WriteDAWG
+	default:
+		return fmt.Errorf("unknown dictionary format: %s", format)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(output), ".spellio-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(file.Name()) }()
+	// CreateTemp makes the file private, but a dictionary is for every user
+	if err = file.Chmod(0o644); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err = write(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(file.Name(), output); err != nil {
+		return err
+	}
+
+	index := ""
+	switch {
+	case c.String("format") == "dawg":
+		index = " as a DAWG"
+	case wt.Backend() == spellcheck.SymSpellBackend:
+		index = " and the symspell index"
+	}
+	fmt.Printf("Compiled %d words%s into %s (%s).\n", wt.IndexStats().Words, index, output, formatBytes(info.Size()))
+	return nil
+}
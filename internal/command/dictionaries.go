@@ -0,0 +1,92 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"spellio/internal/config"
+	"spellio/internal/spellcheck"
+
+	"github.com/urfave/cli/v2"
+)
+
+// dictionaryEnv lists dictionary files to load, separated like PATH
+const dictionaryEnv = "SPELLIO_DICT"
+
+// loadDictionaries loads the dictionary compiled into the binary and stacks the others
+// on top, each adding words or overriding the frequency of words loaded before it. With
+// --lenient, malformed lines are skipped and listed in the Skipped of their dictionary,
+// see warnSkipped. Snapshots and DAWGs are detected by their header and loaded directly.
+func loadDictionaries(wt *spellcheck.WordTrie, c *cli.Context) ([]spellcheck.DictionaryInfo, error) {
+	paths, err := dictionaryPaths(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// A snapshot or DAWG already holds the built-in dictionary, so it is only loaded when
+	// none is listed. Every listed dictionary still stacks in order: a text file before a
+	// snapshot adds the words the snapshot lacks.
+	compiled := false
+	for _, path := range paths {
+		ok, err := spellcheck.IsCompiled(path)
+		if err != nil {
+			return nil, err
+		}
+		compiled = compiled || ok
+	}
+
+	var dictionaries []spellcheck.DictionaryInfo
+	if !compiled {
+		info, err := wt.LoadDefaultDictionary()
+		if err != nil {
+			return nil, err
+		}
+		dictionaries = append(dictionaries, info)
+	}
+	for _, path := range paths {
+		info, err := wt.LoadDictionary(path, c.Bool("lenient"))
+		if err != nil {
+			return nil, err
+		}
+		dictionaries = append(dictionaries, info)
+	}
+	return dictionaries, nil
+}
+
+// warnSkipped prints a warning for every line a lenient load skipped. Only configure
+// calls it, so that commands loading the dictionaries again do not repeat the warnings.
+func warnSkipped(dictionaries []spellcheck.DictionaryInfo) {
+	for _, info := range dictionaries {
+		for _, skipped := range info.Skipped {
+			_, _ = fmt.Fprintf(os.Stderr, "warning: skipped %v\n", skipped)
+		}
+	}
+}
+
+// dictionaryPaths lists the dictionaries to load, lowest priority first: the .txt, .snap
+// and .dawg files of each data directory in name order, then those of SPELLIO_DICT, then
+// every --dict
+func dictionaryPaths(c *cli.Context) ([]string, error) {
+	var paths []string
+	for _, dir := range config.DataDirs() {
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if ext := filepath.Ext(entry.Name()); !entry.IsDir() && (ext == ".txt" || ext == ".snap" || ext == ".dawg") {
+				paths = append(paths, filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+	for _, path := range filepath.SplitList(os.Getenv(dictionaryEnv)) {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return append(paths, c.StringSlice("dict")...), nil
+}
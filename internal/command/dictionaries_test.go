@@ -0,0 +1,68 @@
+package command
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"spellio/internal/spellcheck"
+	"strings"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+)
+
+func TestLoadDictionariesStacksAroundSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dir)
+	t.Setenv(dictionaryEnv, "")
+
+	compiled := spellcheck.NewWordTrie()
+	if _, err := compiled.LoadWords("base.txt", strings.NewReader("the,100\nsnapword,40\nshared,5\n"), false); err != nil {
+		t.Fatal(err)
+	}
+	snap := filepath.Join(dir, "base.snap")
+	file, err := os.Create(snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = compiled.WriteSnapshot(file); err != nil {
+		t.Fatal(err)
+	}
+	if err = file.Close(); err != nil {
+		t.Fatal(err)
+	}
+	before := filepath.Join(dir, "before.txt")
+	after := filepath.Join(dir, "after.txt")
+	if err = os.WriteFile(before, []byte("earlyword,7\nshared,1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(after, []byte("lateword,9\nthe,3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	flags := flag.NewFlagSet("spellio", flag.ContinueOnError)
+	dict := cli.NewStringSlice(before, snap, after)
+	flags.Var(dict, "dict", "")
+	flags.Bool("lenient", false, "")
+	wt := spellcheck.NewWordTrie()
+	dictionaries, err := loadDictionaries(wt, cli.NewContext(cli.NewApp(), flags, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dictionaries) != 3 {
+		t.Errorf("loaded %d dictionaries, want the 3 listed without the built-in one", len(dictionaries))
+	}
+	for word, want := range map[string]int{
+		"earlyword": 7,  // from the text file before the snapshot
+		"shared":    5,  // the snapshot overrides the file before it
+		"snapword":  40, // from the snapshot
+		"the":       3,  // the file after the snapshot overrides it
+		"lateword":  9,  // from the file after the snapshot
+		"receive":   0,  // the built-in dictionary is left out
+	} {
+		if got := wt.GetWordFrequency(word); got != want {
+			t.Errorf("GetWordFrequency(%q) = %d, want %d", word, got, want)
+		}
+	}
+}
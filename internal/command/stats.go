@@ -0,0 +1,104 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"spellio/internal/spellcheck"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// sampleMisspellings is the lookup workload used when stats is run without arguments
+var sampleMisspellings = []string{
+	"recieve", "definately", "teh", "seperate", "heloo", "mispelled", "acommodate",
+	"wierd", "becuase", "occured", "speling", "langauge", "goverment", "tommorow",
+	"beleive", "adress", "freind", "untill", "wich", "thier",
+}
+
+// StatsCommand reports on the dictionaries configure loaded into wt, see dictionaryLoad
+func StatsCommand(wt *spellcheck.WordTrie) func(*cli.Context) error {
+	return func(c *cli.Context) error { return statsCommand(wt, c) }
+}
+
+func statsCommand(wt *spellcheck.WordTrie, c *cli.Context) error {
+	words := sampleMisspellings
+	if c.NArg() > 0 {
+		words = c.Args().Slice()
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "BACKEND\tBUILD\tMEMORY\tENTRIES\tAVG LOOKUP")
+
+	for _, backend := range spellcheck.Backends() {
+		before := heapInUse()
+		if err := wt.UseBackend(backend); err != nil {
+			return err
+		}
+		memory := heapInUse() - before
+		stats := wt.IndexStats()
+		if backend == spellcheck.TrieBackend {
+			stats.BuildTime, memory = loaded.time, loaded.memory
+		}
+
+		start := time.Now()
+		for _, word := range words {
+			wt.FindCandidates(word, spellcheck.DefaultMaxDistance, 1_000_000)
+		}
+		avg := time.Since(start) / time.Duration(len(words))
+
+		_, _ = fmt.Fprintf(w, "%s\t%v\t%s\t%d\t%v\n",
+			backend, stats.BuildTime.Round(time.Millisecond), formatBytes(memory), stats.Entries, avg.Round(time.Microsecond))
+	}
+	_ = w.Flush()
+
+	fmt.Printf("\n%d dictionary words, %d lookups per backend at %s distance %d, bktree keyed on %s.\n",
+		wt.IndexStats().Words, len(words), c.String("distance"), spellcheck.DefaultMaxDistance, c.String("bk-metric"))
+	for _, info := range loaded.dictionaries {
+		fmt.Printf("  %s: %d words%s\n", info.Name, info.Words, formatMetadata(info))
+	}
+	return nil
+}
+
+func formatMetadata(info spellcheck.DictionaryInfo) string {
+	var parts []string
+	if info.Language != "" {
+		parts = append(parts, "language "+info.Language)
+	}
+	if info.Version != "" {
+		parts = append(parts, "version "+info.Version)
+	}
+	if info.Source != "" {
+		parts = append(parts, "from "+info.Source)
+	}
+	if len(info.Skipped) > 0 {
+		parts = append(parts, fmt.Sprintf("%d lines skipped", len(info.Skipped)))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+func heapInUse() int64 {
+	runtime.GC()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return int64(m.HeapInuse)
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
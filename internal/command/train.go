@@ -0,0 +1,62 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"spellio/internal/spellcheck"
+
+	"github.com/urfave/cli/v2"
+)
+
+func TrainCommand() func(*cli.Context) error {
+	return func(c *cli.Context) error { return trainCommand(c) }
+}
+
+func trainCommand(c *cli.Context) error {
+	if c.NArg() == 0 {
+		return fmt.Errorf("usage: spellio train [--output model] <pairs-file>...")
+	}
+
+	model := spellcheck.NewChannelModel()
+	for _, filename := range c.Args().Slice() {
+		pairs, err := spellcheck.LoadTrainingPairs(filename)
+		if err != nil {
+			return err
+		}
+		for _, pair := range pairs {
+			model.Train(pair[0], pair[1])
+		}
+	}
+
+	output := c.String("output")
+	if output == "" {
+		return model.Save(os.Stdout)
+	}
+	file, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err = model.Save(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Trained on %d pairs, model written to %s.\n", model.Pairs, output)
+	return nil
+}
+
+// applyChannelModel switches ranking to the noisy-channel model named by --model, if any
+func applyChannelModel(wt *spellcheck.WordTrie, c *cli.Context) error {
+	path := c.String("model")
+	if path == "" {
+		return nil
+	}
+	model, err := spellcheck.LoadChannelModel(path)
+	if err != nil {
+		return err
+	}
+	wt.SetChannelModel(model)
+	return nil
+}
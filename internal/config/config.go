@@ -0,0 +1,72 @@
+// Package config reads the spellio config file, a list of "key = value" lines whose
+// keys are the names of the global command-line flags, and locates spellio's data files.
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultPath returns $XDG_CONFIG_HOME/spellio/config, falling back to ~/.config
+func DefaultPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "spellio", "config")
+}
+
+// DataDirs returns the directories searched for dictionaries, system-wide first:
+// /usr/share/spellio, then $XDG_DATA_HOME/spellio, falling back to ~/.local/share
+func DataDirs() []string {
+	dirs := []string{filepath.Join("/usr", "share", "spellio")}
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return dirs
+		}
+		dir = filepath.Join(home, ".local", "share")
+	}
+	return append(dirs, filepath.Join(dir, "spellio"))
+}
+
+// Load reads the config file at path. A missing file yields no values and no error so
+// that running without a config is the default.
+func Load(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected key = value", path, line)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if key == "" {
+			return nil, fmt.Errorf("%s:%d: missing key", path, line)
+		}
+		values[key] = strings.Trim(value, `"`)
+	}
+	return values, scanner.Err()
+}
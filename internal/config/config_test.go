@@ -0,0 +1,54 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			text: "# spellio\n\nlayout = dvorak\n  backend=symspell  \ndict = \"/home/me/my words.txt\"\nsimilarity-weight = 1.5\n",
+			want: map[string]string{"layout": "dvorak", "backend": "symspell", "dict": "/home/me/my words.txt", "similarity-weight": "1.5"},
+		},
+		{name: "empty", text: "# nothing set\n", want: map[string]string{}},
+		{name: "missing equals", text: "layout = dvorak\nbackend symspell\n", wantErr: ":2: expected key = value"},
+		{name: "missing key", text: "\n\n = dvorak\n", wantErr: ":3: missing key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config")
+			if err := os.WriteFile(path, []byte(tt.text), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := Load(path)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != path+tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, path+tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Load = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	values, err := Load(filepath.Join(t.TempDir(), "missing"))
+	if values != nil || err != nil {
+		t.Errorf("Load of a missing file = %v, %v, want no values and no error", values, err)
+	}
+}
@@ -0,0 +1,154 @@
+package spellcheck
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultMaxDistance is the edit distance used by AutocorrectMultiple when none is given
+const DefaultMaxDistance = 2
+
+// Backend selects the data structure FindCandidates searches
+type Backend int
+
+const (
+	// TrieBackend walks the word trie, pruning subtrees beyond the distance limit
+	TrieBackend Backend = iota
+	// SymSpellBackend probes a precomputed symmetric-delete index
+	SymSpellBackend
+	// BKTreeBackend runs radius queries against a BK-tree keyed on a distance metric
+	BKTreeBackend
+)
+
+var backendNames = map[Backend]string{
+	TrieBackend:     "trie",
+	SymSpellBackend: "symspell",
+	BKTreeBackend:   "bktree",
+}
+
+func (b Backend) String() string {
+	if name, ok := backendNames[b]; ok {
+		return name
+	}
+	return fmt.Sprintf("Backend(%d)", int(b))
+}
+
+// Backends lists every available backend in declaration order
+func Backends() []Backend {
+	return []Backend{TrieBackend, SymSpellBackend, BKTreeBackend}
+}
+
+func ParseBackend(name string) (Backend, error) {
+	for _, b := range Backends() {
+		if strings.EqualFold(name, b.String()) {
+			return b, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown backend: %s", name)
+}
+
+// IndexStats describes the index behind a backend. Entries counts trie nodes, or DAWG
+// states, for the trie backend, (delete, word) pairs for the symspell backend and tree
+// nodes for the bktree backend.
+type IndexStats struct {
+	Backend   Backend
+	Words     int
+	Entries   int
+	BuildTime time.Duration
+}
+
+// UseBackend switches candidate search to b, building its index if it does not exist yet
+func (wt *WordTrie) UseBackend(b Backend) error {
+	switch b {
+	case TrieBackend:
+	case SymSpellBackend:
+		if wt.symspell == nil {
+			wt.symspell = newSymSpellIndex(wt, DefaultMaxDistance)
+		}
+	case BKTreeBackend:
+		if wt.bktree == nil {
+			wt.bktree = newBKTreeFromTrie(wt, wt.bkMetric)
+		}
+	default:
+		return fmt.Errorf("unknown backend: %v", b)
+	}
+	wt.backend = b
+	wt.cache.clear()
+	return nil
+}
+
+func (wt *WordTrie) Backend() Backend {
+	return wt.backend
+}
+
+// SetBKTreeMetric selects the metric the bktree backend is keyed on, and ranks by when it
+// is weighted, see rankCost. An existing tree is discarded and, if the bktree backend is
+// active, rebuilt on the new metric.
+func (wt *WordTrie) SetBKTreeMetric(m Metric) error {
+	wt.bkMetric = m
+	wt.cache.clear()
+	if wt.bktree == nil {
+		return nil
+	}
+	wt.bktree = nil
+	if wt.backend == BKTreeBackend {
+		return wt.UseBackend(BKTreeBackend)
+	}
+	return nil
+}
+
+// IndexStats reports on the index behind the active backend
+func (wt *WordTrie) IndexStats() IndexStats {
+	switch wt.backend {
+	case SymSpellBackend:
+		return wt.symspell.stats()
+	case BKTreeBackend:
+		return wt.bktree.stats()
+	default:
+		stats := IndexStats{Backend: TrieBackend, Words: wt.words}
+		if wt.dawg != nil {
+			// The root of the overlay is the DAWG's start state
+			stats.Entries = wt.dawg.States() - 1
+		}
+		var count func(n *LetterNode)
+		count = func(n *LetterNode) {
+			stats.Entries++
+			for _, child := range n.Children {
+				count(child)
+			}
+		}
+		count(wt.Root)
+		return stats
+	}
+}
+
+// candidateSink collects the candidates of a search. A parallel search has each subtree
+// searched into a sink of its own from fork and merges them back in subtree order, so a
+// sink that keeps the best k of its candidates bounds what the search holds on to.
+type candidateSink interface {
+	add(candidate string, dist, frequency int)
+	fork() candidateSink
+	merge(candidateSink)
+}
+
+func (wt *WordTrie) findCandidates(word string, maxDist int, sink candidateSink) {
+	switch {
+	case wt.backend == SymSpellBackend && maxDist <= wt.symspell.maxDist:
+		wt.symspell.lookup(word, maxDist, wt.distance, sink.add)
+	case wt.backend == BKTreeBackend:
+		// A transposition costs two edits in a metric without them, so widen the radius
+		radius := maxDist * wt.bktree.metric.Scale
+		if wt.distance.Transpositions && !wt.bktree.metric.Transpositions {
+			radius *= 2
+		}
+		// The tree may be keyed on a weighted metric, so hits are re-measured with the active distance
+		wt.bktree.Search(word, radius, func(candidate string, _, frequency int) {
+			if dist := wt.distance.Distance(word, candidate, maxDist); dist <= maxDist {
+				sink.add(candidate, dist, frequency)
+			}
+		})
+	default:
+		wt.searchTrie(word, maxDist, wt.distance.Transpositions, sink)
+	}
+}
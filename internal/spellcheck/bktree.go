@@ -0,0 +1,98 @@
+package spellcheck
+
+import "time"
+
+type bkNode struct {
+	word      string
+	frequency int
+	children  map[int]*bkNode
+}
+
+// BKTree indexes words in the metric space defined by its Metric. Children are keyed by
+// their distance to the parent, so the triangle inequality lets a radius query skip every
+// branch whose key lies outside [d-radius, d+radius].
+type BKTree struct {
+	metric    Metric
+	root      *bkNode
+	size      int
+	buildTime time.Duration
+}
+
+func NewBKTree(metric Metric) *BKTree {
+	return &BKTree{metric: metric}
+}
+
+func newBKTreeFromTrie(wt *WordTrie, metric Metric) *BKTree {
+	start := time.Now()
+	t := NewBKTree(metric)
+	wt.collectWords(t.Add)
+	t.buildTime = time.Since(start)
+	return t
+}
+
+func (t *BKTree) Metric() Metric {
+	return t.metric
+}
+
+func (t *BKTree) Len() int {
+	return t.size
+}
+
+func (t *BKTree) Add(word string, frequency int) {
+	if t.root == nil {
+		t.root = &bkNode{word: word, frequency: frequency}
+		t.size++
+		return
+	}
+
+	n := t.root
+	for {
+		dist := t.metric.Distance(word, n.word, -1)
+		if dist == 0 && word == n.word {
+			n.frequency = frequency
+			return
+		}
+		child, ok := n.children[dist]
+		if !ok {
+			if n.children == nil {
+				n.children = make(map[int]*bkNode)
+			}
+			n.children[dist] = &bkNode{word: word, frequency: frequency}
+			t.size++
+			return
+		}
+		n = child
+	}
+}
+
+// Search calls fn for every word within radius of word, measured in metric units
+func (t *BKTree) Search(word string, radius int, fn func(string, int, int)) {
+	if t.root == nil {
+		return
+	}
+
+	stack := []*bkNode{t.root}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		dist := t.metric.Distance(word, n.word, -1)
+		if dist <= radius {
+			fn(n.word, dist, n.frequency)
+		}
+		for key, child := range n.children {
+			if key >= dist-radius && key <= dist+radius {
+				stack = append(stack, child)
+			}
+		}
+	}
+}
+
+func (t *BKTree) stats() IndexStats {
+	return IndexStats{
+		Backend:   BKTreeBackend,
+		Words:     t.size,
+		Entries:   t.size,
+		BuildTime: t.buildTime,
+	}
+}
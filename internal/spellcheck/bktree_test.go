@@ -0,0 +1,28 @@
+package spellcheck
+
+import (
+	"spellio/levenshtein"
+	"testing"
+)
+
+func TestBKTreeKeyboardMetricRanks(t *testing.T) {
+	wt := NewWordTrie()
+	wt.Insert("log", 500)
+	wt.Insert("big", 1000)
+	wt.SetCostModel(levenshtein.UniformCosts)
+	if err := wt.UseBackend(BKTreeBackend); err != nil {
+		t.Fatal(err)
+	}
+
+	// Both are one substitution away, so the more frequent word wins under damerau...
+	if got, _ := wt.Autocorrect("lig"); got.Word != "big" {
+		t.Errorf("damerau: Autocorrect(lig) = %q, want big", got.Word)
+	}
+	// ...but "i" and "o" are neighbours on the keyboard, "l" and "b" are not
+	if err := wt.SetBKTreeMetric(KeyboardMetric); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := wt.Autocorrect("lig"); got.Word != "log" {
+		t.Errorf("keyboard: Autocorrect(lig) = %q, want log", got.Word)
+	}
+}
@@ -0,0 +1,142 @@
+package spellcheck
+
+import (
+	"container/list"
+	"slices"
+	"sync"
+)
+
+// DefaultCacheSize is the number of lookups a new WordTrie remembers the corrections of
+const DefaultCacheSize = 10_000
+
+// CacheStats counts how often AutocorrectMultiple was answered from the cache
+type CacheStats struct {
+	Hits, Misses, Evictions uint64
+	// Size is the number of lookups cached, at most Capacity
+	Size, Capacity int
+}
+
+// HitRate is the share of cached lookups that were hits, 0 before any lookup
+func (s CacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// cacheKey identifies a lookup by its normalized word and distance limit; the options
+// set on the WordTrie clear the cache when they change instead
+type cacheKey struct {
+	word    string
+	maxDist int
+}
+
+// cacheEntry holds the best corrections of a lookup, up to limit of them. Fewer suggestions
+// are a prefix of the list, so an entry answers any lookup asking for at most limit.
+type cacheEntry struct {
+	key         cacheKey
+	limit       int
+	corrections []Correction
+}
+
+// covers tells whether the entry holds the best limit corrections
+func (e *cacheEntry) covers(limit int) bool {
+	return limit <= e.limit || len(e.corrections) < e.limit
+}
+
+// correctionCache is a least-recently-used cache of corrections, safe for concurrent use.
+// A capacity of 0 turns it off.
+type correctionCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[cacheKey]*list.Element
+	// order lists the entries most recently used first
+	order *list.List
+
+	hits, misses, evictions uint64
+}
+
+func newCorrectionCache(capacity int) *correctionCache {
+	return &correctionCache{capacity: capacity, entries: make(map[cacheKey]*list.Element), order: list.New()}
+}
+
+// get returns a copy of the best limit corrections cached for key, so callers may modify them
+func (c *correctionCache) get(key cacheKey, limit int) ([]Correction, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.capacity == 0 {
+		return nil, false
+	}
+	e, ok := c.entries[key]
+	if !ok || !e.Value.(*cacheEntry).covers(limit) {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	c.order.MoveToFront(e)
+	corrections := e.Value.(*cacheEntry).corrections
+	return slices.Clone(corrections[:min(limit, len(corrections))]), true
+}
+
+func (c *correctionCache) put(key cacheKey, limit int, corrections []Correction) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.capacity == 0 {
+		return
+	}
+	entry := &cacheEntry{key: key, limit: limit, corrections: slices.Clone(corrections)}
+	if e, ok := c.entries[key]; ok {
+		if !e.Value.(*cacheEntry).covers(limit) {
+			e.Value = entry
+		}
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	c.evict()
+}
+
+// evict drops the least recently used entries beyond capacity
+func (c *correctionCache) evict() {
+	for c.order.Len() > c.capacity {
+		e := c.order.Back()
+		c.order.Remove(e)
+		delete(c.entries, e.Value.(*cacheEntry).key)
+		c.evictions++
+	}
+}
+
+// clear drops every entry, as their corrections may no longer be right
+func (c *correctionCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.order.Len() == 0 {
+		return
+	}
+	clear(c.entries)
+	c.order.Init()
+}
+
+func (c *correctionCache) resize(capacity int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.capacity = max(capacity, 0)
+	c.evict()
+}
+
+func (c *correctionCache) stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{Hits: c.hits, Misses: c.misses, Evictions: c.evictions, Size: c.order.Len(), Capacity: c.capacity}
+}
+
+// SetCacheSize sets how many lookups AutocorrectMultiple remembers the corrections of,
+// dropping the least recently used beyond n; 0 turns the cache off
+func (wt *WordTrie) SetCacheSize(n int) {
+	wt.cache.resize(n)
+}
+
+// CacheStats reports the hits, misses and evictions of the correction cache so far
+func (wt *WordTrie) CacheStats() CacheStats {
+	return wt.cache.stats()
+}
@@ -0,0 +1,139 @@
+package spellcheck
+
+import (
+	"slices"
+	"spellio/levenshtein"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newCorrectionCache(2)
+	a, b, d := cacheKey{"a", 2}, cacheKey{"b", 2}, cacheKey{"d", 2}
+	c.put(a, 1, []Correction{{Word: "at"}})
+	c.put(b, 1, []Correction{{Word: "be"}})
+	if _, ok := c.get(a, 1); !ok {
+		t.Fatal("a missing before capacity was reached")
+	}
+	// b is now the least recently used
+	c.put(d, 1, []Correction{{Word: "do"}})
+	if _, ok := c.get(b, 1); ok {
+		t.Error("b was kept, want it evicted")
+	}
+	for _, key := range []cacheKey{a, d} {
+		if _, ok := c.get(key, 1); !ok {
+			t.Errorf("%q was evicted", key.word)
+		}
+	}
+	if stats := c.stats(); stats.Size != 2 || stats.Evictions != 1 || stats.Hits != 3 || stats.Misses != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	c.resize(1)
+	if stats := c.stats(); stats.Size != 1 || stats.Evictions != 2 {
+		t.Errorf("after resize, stats = %+v", stats)
+	}
+	c.resize(0)
+	c.put(a, 1, []Correction{{Word: "at"}})
+	if _, ok := c.get(a, 1); ok {
+		t.Error("a cache of capacity 0 answered a lookup")
+	}
+}
+
+func TestCacheEntryCovers(t *testing.T) {
+	c := newCorrectionCache(10)
+	key := cacheKey{"teh", 2}
+	corrections := []Correction{{Word: "the"}, {Word: "tea"}, {Word: "ten"}}
+	c.put(key, 3, corrections)
+
+	got, ok := c.get(key, 2)
+	if !ok || !slices.Equal(got, corrections[:2]) {
+		t.Errorf("get(2) = %v, %v, want the first 2 of the 3 cached", got, ok)
+	}
+	got[0].Word = "changed"
+	if got, _ := c.get(key, 1); got[0].Word != "the" {
+		t.Error("modifying a result changed the cache")
+	}
+	if _, ok := c.get(key, 5); ok {
+		t.Error("3 cached corrections answered a lookup of 5")
+	}
+
+	// An entry with fewer corrections than it asked for holds all there are
+	c.put(key, 10, corrections)
+	if got, ok := c.get(key, 20); !ok || len(got) != 3 {
+		t.Errorf("get(20) = %v, %v, want all 3", got, ok)
+	}
+	// A smaller lookup does not replace an entry covering it
+	c.put(key, 1, corrections[:1])
+	if got, ok := c.get(key, 3); !ok || len(got) != 3 {
+		t.Errorf("get(3) after a smaller put = %v, %v", got, ok)
+	}
+}
+
+func TestCacheClearedOnChange(t *testing.T) {
+	wt := NewWordTrie()
+	if _, err := wt.LoadWords("small.txt", strings.NewReader(smallDictionary), false); err != nil {
+		t.Fatal(err)
+	}
+	if got, found := wt.Autocorrect("frobnicat"); found {
+		t.Fatalf("Autocorrect(frobnicat) = %q before frobnicate was added", got.Word)
+	}
+	// A new word is found at once
+	wt.Insert("frobnicate", 10)
+	if got, _ := wt.Autocorrect("frobnicat"); got.Word != "frobnicate" {
+		t.Errorf("after Insert, Autocorrect(frobnicat) = %q, want frobnicate", got.Word)
+	}
+
+	for name, change := range map[string]func() error{
+		"SetChannelModel": func() error { wt.SetChannelModel(NewChannelModel()); return nil },
+		"SetCostModel":    func() error { wt.SetCostModel(levenshtein.UniformCosts); return nil },
+		"SetDistance":     func() error { return wt.SetDistance(LevenshteinMetric) },
+		"SetSimilarity":   func() error { return wt.SetSimilarity(JaroSimilarity, 2) },
+		"SetBKTreeMetric": func() error { return wt.SetBKTreeMetric(KeyboardMetric) },
+		"UseBackend":      func() error { return wt.UseBackend(SymSpellBackend) },
+	} {
+		wt.Autocorrect("nite")
+		if wt.CacheStats().Size == 0 {
+			t.Fatal("nothing cached")
+		}
+		if err := change(); err != nil {
+			t.Fatal(err)
+		}
+		if size := wt.CacheStats().Size; size != 0 {
+			t.Errorf("%s left %d lookups cached", name, size)
+		}
+	}
+}
+
+func TestCacheConcurrentAutocorrect(t *testing.T) {
+	wt := NewWordTrie()
+	if _, err := wt.LoadWords("small.txt", strings.NewReader(smallDictionary), false); err != nil {
+		t.Fatal(err)
+	}
+	wt.SetCacheSize(3)
+	words := []string{"thes", "recieve", "nite", "spel", "fone", "knigt", "thn"}
+	want := make([]Correction, len(words))
+	for i, word := range words {
+		want[i], _ = wt.Autocorrect(word)
+	}
+
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range 200 {
+				i := (g + n) % len(words)
+				if got, _ := wt.Autocorrect(words[i]); got != want[i] {
+					t.Errorf("Autocorrect(%q) = %+v, want %+v", words[i], got, want[i])
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if stats := wt.CacheStats(); stats.Hits == 0 || stats.Evictions == 0 || stats.Size > 3 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
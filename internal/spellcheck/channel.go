@@ -0,0 +1,269 @@
+package spellcheck
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"os"
+	"slices"
+	"spellio/levenshtein"
+	"strconv"
+	"strings"
+)
+
+// ChannelModel is the error model of a noisy-channel corrector: confusion counts learned
+// from (misspelling, correction) pairs that estimate P(typo|word), the probability of
+// typing typo when word was meant. Pair keys are ordered as {intended, typed}.
+type ChannelModel struct {
+	Pairs int
+
+	// Chars counts every rune of the training corrections, the chances to make an error
+	Chars          map[rune]int
+	Substitutions  map[[2]rune]int
+	Deletions      map[rune]int
+	Insertions     map[rune]int
+	Transpositions map[[2]rune]int
+}
+
+// channelCostScale turns negative log probabilities into integer edit costs, so that the
+// levenshtein package can find the most probable alignment as the cheapest one
+const channelCostScale = 100
+
+func NewChannelModel() *ChannelModel {
+	return &ChannelModel{
+		Chars:          make(map[rune]int),
+		Substitutions:  make(map[[2]rune]int),
+		Deletions:      make(map[rune]int),
+		Insertions:     make(map[rune]int),
+		Transpositions: make(map[[2]rune]int),
+	}
+}
+
+// Train counts the edits of the cheapest alignment turning correction into misspelling
+func (m *ChannelModel) Train(misspelling, correction string) {
+	misspelling, correction = strings.ToLower(misspelling), strings.ToLower(correction)
+	m.Pairs++
+	for _, r := range correction {
+		m.Chars[r]++
+	}
+	for _, e := range levenshtein.OSAAlign(correction, misspelling) {
+		from, to := []rune(e.From), []rune(e.To)
+		switch e.Op {
+		case levenshtein.Substitute:
+			m.Substitutions[[2]rune{from[0], to[0]}]++
+		case levenshtein.Delete:
+			m.Deletions[from[0]]++
+		case levenshtein.Insert:
+			m.Insertions[to[0]]++
+		case levenshtein.Transpose:
+			m.Transpositions[[2]rune{from[0], from[1]}]++
+		}
+	}
+}
+
+// LoadTrainingPairs reads one "misspelling correction" pair per line, separated by
+// whitespace or a comma; blank lines and lines starting with # are ignored
+func LoadTrainingPairs(filename string) ([][2]string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var pairs [][2]string
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected a misspelling and its correction", filename, line)
+		}
+		pairs = append(pairs, [2]string{fields[0], fields[1]})
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	return pairs, nil
+}
+
+// Save writes the model in the format LoadChannelModel reads:
+//
+//	pairs 312
+//	char e 1402
+//	substitute e a 12
+//	delete e 7
+//	insert s 9
+//	transpose i e 4
+func (m *ChannelModel) Save(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	_, _ = fmt.Fprintln(bw, "# spellio noisy-channel model")
+	_, _ = fmt.Fprintf(bw, "pairs %d\n", m.Pairs)
+	for _, r := range slices.Sorted(maps.Keys(m.Chars)) {
+		_, _ = fmt.Fprintf(bw, "char %c %d\n", r, m.Chars[r])
+	}
+	for _, p := range slices.SortedFunc(maps.Keys(m.Substitutions), comparePairs) {
+		_, _ = fmt.Fprintf(bw, "substitute %c %c %d\n", p[0], p[1], m.Substitutions[p])
+	}
+	for _, r := range slices.Sorted(maps.Keys(m.Deletions)) {
+		_, _ = fmt.Fprintf(bw, "delete %c %d\n", r, m.Deletions[r])
+	}
+	for _, r := range slices.Sorted(maps.Keys(m.Insertions)) {
+		_, _ = fmt.Fprintf(bw, "insert %c %d\n", r, m.Insertions[r])
+	}
+	for _, p := range slices.SortedFunc(maps.Keys(m.Transpositions), comparePairs) {
+		_, _ = fmt.Fprintf(bw, "transpose %c %c %d\n", p[0], p[1], m.Transpositions[p])
+	}
+	return bw.Flush()
+}
+
+func comparePairs(a, b [2]rune) int {
+	if a[0] != b[0] {
+		return int(a[0] - b[0])
+	}
+	return int(a[1] - b[1])
+}
+
+func LoadChannelModel(filename string) (*ChannelModel, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	m := NewChannelModel()
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if err = m.parseLine(scanner.Text()); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", filename, line, err)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *ChannelModel) parseLine(text string) error {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+		return nil
+	}
+
+	key, args := strings.ToLower(fields[0]), fields[1:]
+	if len(args) == 0 {
+		return fmt.Errorf("missing count for %q", key)
+	}
+	count, err := strconv.Atoi(args[len(args)-1])
+	if err != nil || count < 0 {
+		return fmt.Errorf("invalid count %q", args[len(args)-1])
+	}
+	runes, err := parseCostRunes(args[:len(args)-1])
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case key == "pairs" && len(runes) == 0:
+		m.Pairs = count
+	case key == "char" && len(runes) == 1:
+		m.Chars[runes[0]] = count
+	case key == "substitute" && len(runes) == 2:
+		m.Substitutions[[2]rune{runes[0], runes[1]}] = count
+	case key == "delete" && len(runes) == 1:
+		m.Deletions[runes[0]] = count
+	case key == "insert" && len(runes) == 1:
+		m.Insertions[runes[0]] = count
+	case key == "transpose" && len(runes) == 2:
+		m.Transpositions[[2]rune{runes[0], runes[1]}] = count
+	default:
+		return fmt.Errorf("unknown entry %q with %d characters", key, len(runes))
+	}
+	return nil
+}
+
+// Costs returns the model as edit costs of -log P(edit), scaled by channelCostScale.
+// Every count is smoothed towards the overall rate of its kind of edit, so that edits
+// missing from the training pairs stay possible but rare.
+func (m *ChannelModel) Costs() levenshtein.CostModel {
+	c := &channelCosts{model: m, alphabet: float64(max(len(m.Chars), 26))}
+	for _, n := range m.Chars {
+		c.chars += n
+	}
+	rate := func(total int) float64 {
+		return float64(total+1) / (float64(c.chars) + c.alphabet)
+	}
+	c.substitutionRate = rate(sumCounts(m.Substitutions)) / c.alphabet
+	c.deletionRate = rate(sumCounts(m.Deletions))
+	c.insertionRate = rate(sumCounts(m.Insertions)) / c.alphabet
+	c.transpositionRate = rate(sumCounts(m.Transpositions)) / c.alphabet
+	return c
+}
+
+func sumCounts[K comparable](counts map[K]int) int {
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	return total
+}
+
+type channelCosts struct {
+	model    *ChannelModel
+	chars    int
+	alphabet float64
+
+	substitutionRate, deletionRate, insertionRate, transpositionRate float64
+}
+
+// cost estimates P = (count + alphabet·rate) / (chances + alphabet) and returns -log P
+func (c *channelCosts) cost(count, chances int, rate float64) int {
+	p := (float64(count) + c.alphabet*rate) / (float64(chances) + c.alphabet)
+	return int(math.Round(-math.Log(p) * channelCostScale))
+}
+
+func (c *channelCosts) Insertion(r rune) int {
+	return c.cost(c.model.Insertions[r], c.chars, c.insertionRate)
+}
+
+func (c *channelCosts) Deletion(r rune) int {
+	return c.cost(c.model.Deletions[r], c.model.Chars[r], c.deletionRate)
+}
+
+func (c *channelCosts) Substitution(a, b rune) int {
+	if a == b {
+		return 0
+	}
+	return c.cost(c.model.Substitutions[[2]rune{a, b}], c.model.Chars[a], c.substitutionRate)
+}
+
+func (c *channelCosts) Transposition(a, b rune) int {
+	return c.cost(c.model.Transpositions[[2]rune{a, b}], c.model.Chars[a], c.transpositionRate)
+}
+
+// SetChannelModel ranks corrections by noisy-channel probability, P(word)·P(typo|word),
+// instead of the distance and frequency formula; nil restores the formula
+func (wt *WordTrie) SetChannelModel(m *ChannelModel) {
+	wt.channel = m
+	wt.channelCosts = nil
+	if m != nil {
+		wt.channelCosts = m.Costs()
+	}
+	wt.cache.clear()
+}
+
+func (wt *WordTrie) ChannelModel() *ChannelModel {
+	return wt.channel
+}
+
+// channelScore is -log(P(candidate)·P(word|candidate)) scaled by channelCostScale, with
+// P(candidate) estimated from the dictionary frequencies
+func (wt *WordTrie) channelScore(word, candidate string, frequency int) float64 {
+	prior := float64(frequency+1) / float64(wt.totalFrequency+wt.words)
+	channel := levenshtein.ModelOSADistance(candidate, word, wt.channelCosts)
+	return -math.Log(prior)*channelCostScale + float64(channel)
+}
@@ -0,0 +1,117 @@
+package spellcheck
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// vowelPairs are misspellings of people who type e for a
+var vowelPairs = [][2]string{
+	{"bed", "bad"}, {"het", "hat"}, {"ceme", "came"}, {"men", "man"},
+	{"thet", "that"}, {"cen", "can"}, {"bedly", "badly"}, {"heppy", "happy"},
+}
+
+func TestChannelModelTrain(t *testing.T) {
+	m := NewChannelModel()
+	for _, pair := range [][2]string{{"teh", "the"}, {"acess", "access"}, {"helllo", "hello"}, {"thw", "The"}} {
+		m.Train(pair[0], pair[1])
+	}
+
+	want := NewChannelModel()
+	want.Pairs = 4
+	for _, word := range []string{"the", "access", "hello", "the"} {
+		for _, r := range word {
+			want.Chars[r]++
+		}
+	}
+	want.Transpositions[[2]rune{'h', 'e'}] = 1
+	want.Deletions['c'] = 1
+	want.Insertions['l'] = 1
+	want.Substitutions[[2]rune{'e', 'w'}] = 1
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("trained model = %+v, want %+v", m, want)
+	}
+}
+
+func TestChannelModelSaveLoad(t *testing.T) {
+	m := NewChannelModel()
+	for _, pair := range append(vowelPairs, [2]string{"teh", "the"}, [2]string{"naïve", "naive"}, [2]string{"acess", "access"}) {
+		m.Train(pair[0], pair[1])
+	}
+	path := filepath.Join(t.TempDir(), "channel.txt")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = m.Save(file); err != nil {
+		t.Fatal(err)
+	}
+	if err = file.Close(); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadChannelModel(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, m) {
+		t.Errorf("loaded model = %+v, want %+v", loaded, m)
+	}
+}
+
+func TestChannelModelRanking(t *testing.T) {
+	wt, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewChannelModel()
+	for _, pair := range vowelPairs {
+		m.Train(pair[0], pair[1])
+	}
+
+	tests := []struct {
+		word, formula, channel string
+	}{
+		{"fet", "get", "fat"},
+		{"trep", "trip", "trap"},
+	}
+	for _, tt := range tests {
+		if got, _ := wt.Autocorrect(tt.word); got.Word != tt.formula {
+			t.Errorf("Autocorrect(%q) = %q, want %q", tt.word, got.Word, tt.formula)
+		}
+	}
+	wt.SetChannelModel(m)
+	for _, tt := range tests {
+		if got, _ := wt.Autocorrect(tt.word); got.Word != tt.channel {
+			t.Errorf("with the channel model, Autocorrect(%q) = %q, want %q", tt.word, got.Word, tt.channel)
+		}
+	}
+	// The more often an edit was seen, the more probable a candidate it explains
+	if fat, fit := wt.channelScore("fet", "fat", 100), wt.channelScore("fet", "fit", 100); fat >= fit {
+		t.Errorf("channelScore(fet, fat) = %.1f, not below channelScore(fet, fit) = %.1f", fat, fit)
+	}
+}
+
+func TestLoadChannelModelErrors(t *testing.T) {
+	tests := []struct {
+		line, wantErr string
+	}{
+		{"pairs", `missing count for "pairs"`},
+		{"char e x", `invalid count "x"`},
+		{"delete e -1", `invalid count "-1"`},
+		{"substitute ea 3", `expected a single character, got "ea"`},
+		{"substitute e 3", `unknown entry "substitute" with 1 characters`},
+		{"swap a b 2", `unknown entry "swap" with 2 characters`},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "channel.txt")
+		if err := os.WriteFile(path, []byte("# comment\n"+tt.line+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := LoadChannelModel(path)
+		if err == nil || err.Error() != path+":2: "+tt.wantErr {
+			t.Errorf("%q: error = %v, want %q", tt.line, err, tt.wantErr)
+		}
+	}
+}
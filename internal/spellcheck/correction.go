@@ -1,9 +1,8 @@
 package spellcheck
 
 import (
+	"maps"
 	"math"
-	"sort"
-	"spellio/levenshtein"
 	"strings"
 )
 
@@ -18,36 +17,43 @@ type Correction struct {
 	Distance   int
 	Frequency  int
 	Confidence float64
+	// Phonetic is set when the correction sounds like the word it corrects
+	Phonetic bool
 }
 
+// FindCandidates returns the N closest words within maxDist, the more frequent first
+// among words at the same distance
 func (wt *WordTrie) FindCandidates(word string, maxDist, N int) []Candidate {
-	var candidates []Candidate
-	wordLen := len(word)
-	wt.collectWords(func(candidate string, frequency int) {
-		candidateLen := len(candidate)
-		if int(math.Abs(float64(wordLen-candidateLen))) > maxDist {
-			return
-		}
-		dist := levenshtein.DistanceWithThreshold(word, candidate, maxDist)
-		if dist <= maxDist {
-			candidates = append(candidates, Candidate{
-				Word:      candidate,
-				Distance:  dist,
-				Frequency: frequency,
-			})
-		}
-	})
-	// Sort by Distance, then Frequency
-	sort.Slice(candidates, func(i, j int) bool {
-		if candidates[i].Distance == candidates[j].Distance {
-			return candidates[i].Frequency > candidates[j].Frequency
-		}
-		return candidates[i].Distance < candidates[j].Distance
-	})
-	if len(candidates) > N {
-		candidates = candidates[:N]
+	top := &candidateTop{newTopK(N, closerCandidate)}
+	wt.findCandidates(word, maxDist, top)
+	return top.top.sorted()
+}
+
+func closerCandidate(a, b *Candidate) bool {
+	if a.Distance != b.Distance {
+		return a.Distance < b.Distance
+	}
+	if a.Frequency != b.Frequency {
+		return a.Frequency > b.Frequency
 	}
-	return candidates
+	return a.Word < b.Word
+}
+
+// candidateTop keeps the best candidates of a search for FindCandidates
+type candidateTop struct {
+	top *topK[Candidate]
+}
+
+func (c *candidateTop) add(candidate string, dist, frequency int) {
+	c.top.push(Candidate{Word: candidate, Distance: dist, Frequency: frequency})
+}
+
+func (c *candidateTop) fork() candidateSink {
+	return &candidateTop{newTopK(c.top.k, closerCandidate)}
+}
+
+func (c *candidateTop) merge(other candidateSink) {
+	c.top.merge(other.(*candidateTop).top)
 }
 
 func (wt *WordTrie) Autocorrect(word string, md ...int) (Correction, bool) {
@@ -59,7 +65,7 @@ func (wt *WordTrie) Autocorrect(word string, md ...int) (Correction, bool) {
 }
 
 func (wt *WordTrie) AutocorrectMultiple(word string, maxSuggestions int, md ...int) []Correction {
-	maxDist := 2
+	maxDist := DefaultMaxDistance
 	if len(md) > 0 {
 		maxDist = md[0]
 	}
@@ -76,13 +82,6 @@ func (wt *WordTrie) AutocorrectMultiple(word string, maxSuggestions int, md ...i
 		}}
 	}
 
-	// Check for pattern-based correction but don't return immediately -
-	// let it be prioritized in the full candidate search
-	var patternCorrection string
-	if correction, exists := commonMisspellings[word]; exists && wt.IsWord(correction) {
-		patternCorrection = correction
-	}
-
 	if wt.isPossessive(word) {
 		baseWord := strings.TrimSuffix(word, "'s")
 		if wt.IsWord(baseWord) {
@@ -96,67 +95,218 @@ func (wt *WordTrie) AutocorrectMultiple(word string, maxSuggestions int, md ...i
 		}
 	}
 
-	candidates := wt.FindCandidates(word, maxDist, 1_000_000)
+	key := cacheKey{word: word, maxDist: maxDist}
+	if corrections, ok := wt.cache.get(key, maxSuggestions); ok {
+		return corrections
+	}
+	corrections := wt.findCorrections(word, maxDist, maxSuggestions)
+	wt.cache.put(key, maxSuggestions, corrections)
+	return corrections
+}
 
-	var corrections []Correction
-	for _, c := range candidates {
-		if c.Word == word {
-			continue
-		}
-		confidence := wt.calculateConfidence(c.Distance, c.Frequency, maxDist)
+// findCorrections searches and ranks the corrections of a lower-case word
+func (wt *WordTrie) findCorrections(word string, maxDist, maxSuggestions int) []Correction {
+	// Check for pattern-based correction but don't return immediately -
+	// let it be prioritized in the full candidate search
+	var patternCorrection string
+	if correction, exists := commonMisspellings[word]; exists && wt.isPhrase(correction) {
+		patternCorrection = correction
+	}
 
-		// Boost confidence for pattern-based corrections
-		if patternCorrection != "" && c.Word == patternCorrection {
-			confidence = 0.98
+	// Words that sound alike may be spelled too differently to be found by the search
+	soundsLike := make(map[string]Candidate)
+	wt.soundsLike(word, minPhoneticKey, func(candidate string, dist, frequency int) {
+		soundsLike[candidate] = Candidate{Word: candidate, Distance: dist, Frequency: frequency}
+	})
+
+	cs := &correctionSearch{
+		ranker:     wt.newRanker(word, maxDist, maxSuggestions),
+		soundsLike: soundsLike,
+		found:      make(map[string]bool),
+		pattern:    patternCorrection,
+	}
+	wt.findCandidates(word, maxDist, cs)
+	for candidate, c := range soundsLike {
+		if !cs.found[candidate] {
+			cs.addCandidate(c, true)
 		}
+	}
 
-		corrections = append(corrections, Correction{
-			Word:       c.Word,
-			Distance:   c.Distance,
-			Frequency:  c.Frequency,
-			Confidence: confidence,
-		})
+	// A run-on word may be several words missing their spaces, one edit per space
+	if pieces, frequency := wt.segment(word); pieces != nil {
+		cs.addCandidate(Candidate{Word: strings.Join(pieces, " "), Distance: len(pieces) - 1, Frequency: frequency}, false)
+	}
+	if patternCorrection != "" && !cs.patternFound {
+		cs.addCandidate(Candidate{
+			Word:      patternCorrection,
+			Distance:  wt.distance.Distance(word, patternCorrection, -1) / wt.distance.Scale,
+			Frequency: wt.phraseFrequency(patternCorrection),
+		}, false)
 	}
 
-	sort.Slice(corrections, func(i, j int) bool {
-		// Primary sort: High-confidence pattern corrections first
-		if corrections[i].Confidence >= 0.98 && corrections[j].Confidence < 0.98 {
-			return true
-		}
-		if corrections[j].Confidence >= 0.98 && corrections[i].Confidence < 0.98 {
-			return false
-		}
+	return cs.corrections()
+}
+
+// correctionSearch ranks the candidates of a search for the corrections of a word
+type correctionSearch struct {
+	*ranker
+	// soundsLike holds the words that sound like the word, and is only read while searching;
+	// found marks those the search reported
+	soundsLike map[string]Candidate
+	found      map[string]bool
+	// pattern is the correction of a common misspelling, ranked first when found
+	pattern      string
+	patternFound bool
+}
+
+func (cs *correctionSearch) add(candidate string, dist, frequency int) {
+	_, phonetic := cs.soundsLike[candidate]
+	if phonetic {
+		cs.found[candidate] = true
+	}
+	cs.addCandidate(Candidate{Word: candidate, Distance: dist, Frequency: frequency}, phonetic)
+}
 
-		// Calculate composite scores that balance distance and frequency
-		// Score = distance - log10(frequency) * scaling_factor
-		// Lower scores rank higher
-		scalingFactor := 0.25
+func (cs *correctionSearch) addCandidate(c Candidate, phonetic bool) {
+	if c.Word == cs.word {
+		return
+	}
+	pattern := cs.pattern != "" && c.Word == cs.pattern
+	cs.patternFound = cs.patternFound || pattern
+	cs.ranker.add(c, phonetic, pattern)
+}
+
+func (cs *correctionSearch) fork() candidateSink {
+	return &correctionSearch{
+		ranker:     cs.wt.newRanker(cs.word, cs.maxDist, cs.top.k),
+		soundsLike: cs.soundsLike,
+		found:      make(map[string]bool),
+		pattern:    cs.pattern,
+	}
+}
+
+func (cs *correctionSearch) merge(other candidateSink) {
+	o := other.(*correctionSearch)
+	cs.ranker.merge(o.ranker)
+	maps.Copy(cs.found, o.found)
+	cs.patternFound = cs.patternFound || o.patternFound
+}
+
+// frequencyWeight scales log10(frequency) against distance in the default ranking
+const frequencyWeight = 0.25
+
+// rankedCorrection is a correction with the key it is ranked by
+type rankedCorrection struct {
+	Correction
+	pattern bool
+	// score is the composite score of the default ranking or the channel score; lower ranks higher
+	score float64
+	// cost is the weighted distance the default ranking scores with, see rankCost
+	cost int
+}
+
+// ranker keeps the best corrections of a word as candidates stream in, scoring each once
+type ranker struct {
+	wt      *WordTrie
+	word    string
+	maxDist int
+	top     *topK[rankedCorrection]
+
+	// best and total normalize the channel posteriors: total sums exp((best-score)/channelCostScale)
+	// over every candidate seen and is rescaled whenever best improves
+	best, total float64
+}
 
-		scoreI := float64(corrections[i].Distance)
-		scoreJ := float64(corrections[j].Distance)
+func (wt *WordTrie) newRanker(word string, maxDist, k int) *ranker {
+	r := &ranker{wt: wt, word: word, maxDist: maxDist, best: math.Inf(1)}
+	r.top = newTopK(k, r.better)
+	return r
+}
 
-		// Apply frequency scaling if frequency > 0
-		if corrections[i].Frequency > 0 {
-			scoreI -= math.Log10(float64(corrections[i].Frequency)) * scalingFactor
+func (r *ranker) add(c Candidate, phonetic, pattern bool) {
+	rc := rankedCorrection{
+		Correction: Correction{Word: c.Word, Distance: c.Distance, Frequency: c.Frequency, Phonetic: phonetic},
+		pattern:    pattern,
+	}
+	if r.wt.channel != nil {
+		rc.score = r.wt.channelScore(r.word, c.Word, c.Frequency)
+		if rc.score < r.best {
+			r.total *= math.Exp((rc.score - r.best) / channelCostScale)
+			r.best = rc.score
 		}
-		if corrections[j].Frequency > 0 {
-			scoreJ -= math.Log10(float64(corrections[j].Frequency)) * scalingFactor
+		r.total += math.Exp((r.best - rc.score) / channelCostScale)
+	} else {
+		// Score = model distance in edits - log10(frequency) * frequencyWeight - similarity bonus
+		cost, unit := r.wt.rankCost(r.word, c.Word)
+		rc.cost = cost
+		dist := float64(cost) / float64(unit)
+		rc.score = rankDistance(dist, phonetic, r.maxDist) - r.wt.similarityBonus(r.word, c.Word)
+		if c.Frequency > 0 {
+			rc.score -= math.Log10(float64(c.Frequency)) * frequencyWeight
 		}
+	}
+	r.top.push(rc)
+}
 
-		// Primary sort by composite score
-		if math.Abs(scoreI-scoreJ) > 0.001 { // Use a small threshold for float comparison
-			return scoreI < scoreJ
-		}
+// merge adds the corrections another ranker of the same word kept, and the candidates it
+// saw to the channel normalization
+func (r *ranker) merge(o *ranker) {
+	if o.total > 0 {
+		best := min(r.best, o.best)
+		r.total = r.total*math.Exp((best-r.best)/channelCostScale) + o.total*math.Exp((best-o.best)/channelCostScale)
+		r.best = best
+	}
+	r.top.merge(o.top)
+}
 
-		// Tie-breaker: keyboard distance
-		keyboardDistI := levenshtein.KeyboardAwareDistance(word, corrections[i].Word)
-		keyboardDistJ := levenshtein.KeyboardAwareDistance(word, corrections[j].Word)
-		return keyboardDistI < keyboardDistJ
-	})
+// scoreQuantum is the resolution formula scores are compared at, so that scores differing
+// only by rounding fall through to the cost. Rounding to it, unlike comparing within a
+// tolerance, keeps the order transitive, and so independent of the order of the search.
+const scoreQuantum = 0.001
+
+func quantizeScore(score float64) float64 {
+	return math.Round(score / scoreQuantum)
+}
 
-	if len(corrections) > maxSuggestions {
-		corrections = corrections[:maxSuggestions]
+func (r *ranker) better(a, b *rankedCorrection) bool {
+	// High-confidence pattern corrections first
+	if a.pattern != b.pattern {
+		return a.pattern
+	}
+	if r.wt.channel != nil {
+		if a.score != b.score {
+			return a.score < b.score
+		}
+		return a.Word < b.Word
 	}
 
+	if qa, qb := quantizeScore(a.score), quantizeScore(b.score); qa != qb {
+		return qa < qb
+	}
+	if a.cost != b.cost {
+		return a.cost < b.cost
+	}
+	return a.Word < b.Word
+}
+
+// corrections returns the corrections kept, best first, with their confidence. With a
+// channel model it is the posterior probability among all the candidates seen.
+func (r *ranker) corrections() []Correction {
+	ranked := r.top.sorted()
+	if len(ranked) == 0 {
+		return nil
+	}
+	corrections := make([]Correction, len(ranked))
+	for i, rc := range ranked {
+		corrections[i] = rc.Correction
+		switch {
+		case rc.pattern:
+			corrections[i].Confidence = 0.98
+		case r.wt.channel != nil:
+			corrections[i].Confidence = math.Exp((r.best-rc.score)/channelCostScale) / r.total
+		default:
+			corrections[i].Confidence = r.wt.calculateConfidence(min(rc.Distance, r.maxDist), rc.Frequency, r.maxDist)
+		}
+	}
 	return corrections
 }
@@ -0,0 +1,33 @@
+package spellcheck
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRankingIsIndependentOfOrder(t *testing.T) {
+	// Scores a tolerance would call equal in pairs, with costs ranking them the other way:
+	// a tolerance compare makes c beat b, b beat a and a beat c
+	corrections := []rankedCorrection{
+		{Correction: Correction{Word: "a"}, score: 0, cost: 30},
+		{Correction: Correction{Word: "b"}, score: 0.0008, cost: 20},
+		{Correction: Correction{Word: "c"}, score: 0.0016, cost: 10},
+	}
+	orders := [][]int{{0, 1, 2}, {0, 2, 1}, {1, 0, 2}, {1, 2, 0}, {2, 0, 1}, {2, 1, 0}}
+	var first []string
+	for _, order := range orders {
+		r := NewWordTrie().newRanker("x", 2, 2)
+		for _, i := range order {
+			r.top.push(corrections[i])
+		}
+		var got []string
+		for _, c := range r.top.sorted() {
+			got = append(got, c.Word)
+		}
+		if first == nil {
+			first = got
+		} else if !slices.Equal(got, first) {
+			t.Errorf("order %v ranks %v, order %v ranks %v", orders[0], first, order, got)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package spellcheck
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"spellio/levenshtein"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// SetCostModel selects the edit costs candidates are ranked by. A candidate's distance
+// under m, in units of an ordinary edit, replaces its edit count in the ranking score,
+// so cheaper edits such as adjacent-key slips rank their corrections higher.
+func (wt *WordTrie) SetCostModel(m levenshtein.CostModel) {
+	wt.costs, wt.costUnit = m, costUnit(m)
+	wt.cache.clear()
+}
+
+// costUnit is the most m charges to insert, delete or substitute runes it is unlikely to
+// single out, which is what it charges for an ordinary edit
+func costUnit(m levenshtein.CostModel) int {
+	const a, b = utf8.MaxRune, utf8.MaxRune - 1
+	return max(m.Insertion(a), m.Deletion(a), m.Substitution(a, b), 1)
+}
+
+// rankCost is the weighted distance a candidate is scored with and what an ordinary edit
+// costs in it. That is the distance under the cost model, except that the bktree backend
+// keyed on a weighted metric such as keyboard ranks by that metric, the one it searches by.
+func (wt *WordTrie) rankCost(word, candidate string) (cost, unit int) {
+	if wt.backend == BKTreeBackend && wt.bktree.metric.Scale > 1 {
+		return wt.bktree.metric.Distance(word, candidate, -1), wt.bktree.metric.Scale
+	}
+	return wt.modelDistance(word, candidate), wt.costUnit
+}
+
+func (wt *WordTrie) CostModel() levenshtein.CostModel {
+	return wt.costs
+}
+
+func (wt *WordTrie) modelDistance(a, b string) int {
+	if wt.distance.Transpositions {
+		return levenshtein.ModelOSADistance(a, b, wt.costs)
+	}
+	return levenshtein.ModelDistance(a, b, wt.costs)
+}
+
+// ParseCostModel resolves "uniform" and "keyboard" to the built-in models, the latter
+// on the given layout. A cost file is given by a path containing a slash or a dot, or
+// by any path after "file:", so that a misspelled model name is reported as such.
+func ParseCostModel(spec string, layout *levenshtein.Layout) (levenshtein.CostModel, error) {
+	switch strings.ToLower(spec) {
+	case "uniform":
+		return levenshtein.UniformCosts, nil
+	case "keyboard":
+		return layout.Costs(), nil
+	}
+	if path, ok := strings.CutPrefix(spec, "file:"); ok {
+		return LoadCostModel(path)
+	}
+	if strings.ContainsAny(spec, "./"+string(os.PathSeparator)) {
+		return LoadCostModel(spec)
+	}
+	return nil, fmt.Errorf("unknown cost model: %s (expected uniform, keyboard or the path of a cost file)", spec)
+}
+
+// LoadCostModel reads a cost file. Each line sets the default cost of an operation or
+// overrides it for specific runes; blank lines and lines starting with # are ignored:
+//
+//	default 10
+//	insert e 8
+//	delete s 7
+//	substitute a e 6
+//	transpose i e 5
+func LoadCostModel(filename string) (*levenshtein.WeightedCosts, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	costs := levenshtein.NewWeightedCosts(10)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if err = parseCostLine(costs, scanner.Text()); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", filename, line, err)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	return costs, nil
+}
+
+func parseCostLine(costs *levenshtein.WeightedCosts, text string) error {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+		return nil
+	}
+
+	op, args := strings.ToLower(fields[0]), fields[1:]
+	if len(args) == 0 {
+		return fmt.Errorf("missing cost for %q", op)
+	}
+	cost, err := strconv.Atoi(args[len(args)-1])
+	if err != nil || cost < 0 {
+		return fmt.Errorf("invalid cost %q", args[len(args)-1])
+	}
+	runes, err := parseCostRunes(args[:len(args)-1])
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case op == "default" && len(runes) == 0:
+		costs.Insert, costs.Delete, costs.Substitute, costs.Transpose = cost, cost, cost, cost
+	case op == "insert" && len(runes) == 0:
+		costs.Insert = cost
+	case op == "insert" && len(runes) == 1:
+		costs.Insertions[runes[0]] = cost
+	case op == "delete" && len(runes) == 0:
+		costs.Delete = cost
+	case op == "delete" && len(runes) == 1:
+		costs.Deletions[runes[0]] = cost
+	case op == "substitute" && len(runes) == 0:
+		costs.Substitute = cost
+	case op == "substitute" && len(runes) == 2:
+		costs.Substitutions[[2]rune{runes[0], runes[1]}] = cost
+	case op == "transpose" && len(runes) == 0:
+		costs.Transpose = cost
+	case op == "transpose" && len(runes) == 2:
+		costs.Transpositions[[2]rune{runes[0], runes[1]}] = cost
+	default:
+		return fmt.Errorf("unknown cost rule %q with %d characters", op, len(runes))
+	}
+	return nil
+}
+
+func parseCostRunes(fields []string) ([]rune, error) {
+	runes := make([]rune, 0, len(fields))
+	for _, f := range fields {
+		r, size := utf8.DecodeRuneInString(f)
+		if size != len(f) {
+			return nil, fmt.Errorf("expected a single character, got %q", f)
+		}
+		runes = append(runes, r)
+	}
+	return runes, nil
+}
+
+// Align returns the edits turning word into correction under the active cost model,
+// with transpositions when the active distance allows them
+func (wt *WordTrie) Align(word, correction string) []levenshtein.Edit {
+	if wt.distance.Transpositions {
+		return levenshtein.ModelOSAAlign(word, correction, wt.costs)
+	}
+	return levenshtein.ModelAlign(word, correction, wt.costs)
+}
@@ -0,0 +1,85 @@
+package spellcheck
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"spellio/levenshtein"
+	"strings"
+	"testing"
+)
+
+func TestLayoutChangesRanking(t *testing.T) {
+	wt, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		layout *levenshtein.Layout
+		word   string
+		want   string
+	}{
+		{levenshtein.QWERTY, "hod", "how"},
+		{levenshtein.Dvorak, "hod", "had"},
+		{levenshtein.QWERTY, "lig", "log"},
+		{levenshtein.Dvorak, "lig", "big"},
+	}
+	for _, tt := range tests {
+		wt.SetCostModel(tt.layout.Costs())
+		if got, _ := wt.Autocorrect(tt.word); got.Word != tt.want {
+			t.Errorf("%s: Autocorrect(%q) = %q, want %q", tt.layout.Name, tt.word, got.Word, tt.want)
+		}
+	}
+}
+
+func TestParseCostModel(t *testing.T) {
+	dir := t.TempDir()
+	costFile := filepath.Join(dir, "costs")
+	if err := os.WriteFile(costFile, []byte("default 10\ndelete s 6\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		spec    string
+		wantErr string
+	}{
+		{spec: "uniform"},
+		{spec: "Keyboard"},
+		{spec: costFile},
+		{spec: "file:" + costFile},
+		{spec: "keybaord", wantErr: "unknown cost model: keybaord"},
+		{spec: "./missing.txt", wantErr: "no such file"},
+		{spec: "file:missing", wantErr: "no such file"},
+	}
+	for _, tt := range tests {
+		m, err := ParseCostModel(tt.spec, levenshtein.QWERTY)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("ParseCostModel(%q): %v", tt.spec, err)
+		case tt.wantErr == "" && m == nil:
+			t.Errorf("ParseCostModel(%q) returned no model", tt.spec)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("ParseCostModel(%q) error = %v, want %q", tt.spec, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCostUnit(t *testing.T) {
+	tests := []struct {
+		name  string
+		model levenshtein.CostModel
+		want  int
+	}{
+		{"uniform", levenshtein.UniformCosts, 1},
+		{"keyboard", levenshtein.KeyboardCosts, 10},
+		{"weighted", levenshtein.NewWeightedCosts(7), 7},
+	}
+	for _, tt := range tests {
+		if got := costUnit(tt.model); got != tt.want {
+			t.Errorf("costUnit(%s) = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+	if _, err := LoadCostModel("missing"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("LoadCostModel(missing) error = %v, want os.ErrNotExist", err)
+	}
+}
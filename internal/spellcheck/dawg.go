@@ -0,0 +1,615 @@
+package spellcheck
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"sort"
+	"spellio/phonetic"
+	"unicode/utf8"
+)
+
+// DAWG is a read-only dictionary stored as a minimized automaton, a directed acyclic
+// word graph: words that end alike share the states of their ending, so it is far
+// smaller than the trie. Every state counts the words it leads to, which numbers the
+// words in alphabetical order and lets a lookup find the frequency of a word in a flat
+// array. The sound-alike index is stored too, as the numbers of the words of every
+// Metaphone key. On Linux the file is memory-mapped, so processes opening the same DAWG
+// share its pages and it takes no heap.
+//
+// The file is little-endian:
+//
+//	magic        "SPELLDWG"
+//	version      uint32
+//	checksum     uint32, CRC-32C of everything after the header
+//	states, edges, words, keys  uint32 each
+//	total        uint64, the sum of all frequencies
+//	states       12 bytes each: first edge, edge count with bit 31 set for a word, words reached
+//	edges        8 bytes each: letter, target state; sorted by letter within a state
+//	frequencies  8 bytes per word, in alphabetical order of the words
+//	keys         8 bytes per key and one more: offset of the key in the key text, index of
+//	             its first word number; sorted by key, the extra one marks where both end
+//	word numbers 4 bytes each, the words of every key in alphabetical order
+//	key text     the keys one after another
+//
+// State 0 is the start and every edge leads to a higher state, so the graph has no cycles.
+type DAWG struct {
+	data                         []byte
+	states, edges, freqs         []byte
+	keys, keyWords, keyText      []byte
+	stateCount, edgeCount, count uint32
+	words                        int
+	total                        int
+	unmap                        func() error
+}
+
+const (
+	dawgMagic = "SPELLDWG"
+	// DAWGVersion changes whenever the layout changes incompatibly
+	DAWGVersion = 3
+
+	dawgHeaderSize = 40
+	dawgStateSize  = 12
+	dawgEdgeSize   = 8
+	dawgKeySize    = 8
+	dawgFinal      = 1 << 31
+)
+
+// OpenDAWG maps a DAWG written by WriteDAWG into memory. The DAWG must be closed once it
+// is no longer in use, after which nothing read from it may be used either.
+func OpenDAWG(filename string) (*DAWG, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	data, unmap, err := mapFile(file, int(info.Size()))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	d, err := newDAWG(data)
+	if err != nil {
+		_ = unmap()
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	d.unmap = unmap
+	return d, nil
+}
+
+// newDAWG checks the header, checksum and structure of data, so that walking the
+// DAWG later can neither run out of bounds nor loop
+func newDAWG(data []byte) (*DAWG, error) {
+	le := binary.LittleEndian
+	if len(data) < dawgHeaderSize || string(data[:len(dawgMagic)]) != dawgMagic {
+		return nil, errors.New("not a spellio DAWG")
+	}
+	if version := le.Uint32(data[8:]); version != DAWGVersion {
+		return nil, fmt.Errorf("DAWG version %d is not supported, recompile it with \"spellio dict compile\"", version)
+	}
+	if crc32.Checksum(data[dawgHeaderSize:], snapshotTable) != le.Uint32(data[12:]) {
+		return nil, errors.New("DAWG checksum mismatch, the file is damaged")
+	}
+
+	d := &DAWG{
+		data:       data,
+		stateCount: le.Uint32(data[16:]),
+		edgeCount:  le.Uint32(data[20:]),
+		words:      int(le.Uint32(data[24:])),
+		count:      le.Uint32(data[28:]),
+		total:      int(le.Uint64(data[32:])),
+	}
+	statesEnd := dawgHeaderSize + uint64(d.stateCount)*dawgStateSize
+	edgesEnd := statesEnd + uint64(d.edgeCount)*dawgEdgeSize
+	freqsEnd := edgesEnd + uint64(d.words)*8
+	keysEnd := freqsEnd + (uint64(d.count)+1)*dawgKeySize
+	if d.stateCount == 0 || keysEnd > uint64(len(data)) {
+		return nil, errCorruptedData
+	}
+	d.states = data[dawgHeaderSize:statesEnd]
+	d.edges = data[statesEnd:edgesEnd]
+	d.freqs = data[edgesEnd:freqsEnd]
+	d.keys = data[freqsEnd:keysEnd]
+	textStart, keyWords := d.key(d.count)
+	keyWordsEnd := keysEnd + uint64(keyWords)*4
+	if keyWordsEnd+uint64(textStart) != uint64(len(data)) {
+		return nil, errCorruptedData
+	}
+	d.keyWords = data[keysEnd:keyWordsEnd]
+	d.keyText = data[keyWordsEnd:]
+	if err := d.checkKeys(); err != nil {
+		return nil, err
+	}
+
+	// Targets are higher states, so the words reached can be checked from the last state back
+	for s := d.stateCount; s > 0; s-- {
+		first, count, final, words := d.state(s - 1)
+		if uint64(first)+uint64(count) > uint64(d.edgeCount) {
+			return nil, errCorruptedData
+		}
+		reached := uint64(0)
+		if final {
+			reached = 1
+		}
+		var prev rune = -1
+		for i := first; i < first+count; i++ {
+			letter, target := d.edge(i)
+			if letter <= prev || !utf8.ValidRune(letter) || target < s || target >= d.stateCount {
+				return nil, errCorruptedData
+			}
+			prev = letter
+			reached += uint64(d.wordsFrom(target))
+		}
+		if reached != uint64(words) {
+			return nil, errCorruptedData
+		}
+	}
+	if d.wordsFrom(0) != uint32(d.words) {
+		return nil, errCorruptedData
+	}
+	return d, nil
+}
+
+// checkKeys checks that the keys are sorted and their offsets and word numbers in range
+func (d *DAWG) checkKeys() error {
+	if text, words := d.key(0); text != 0 || words != 0 {
+		return errCorruptedData
+	}
+	var prev string
+	for i := range d.count {
+		text, words := d.key(i)
+		nextText, nextWords := d.key(i + 1)
+		if nextText < text || nextWords < words || int(nextText) > len(d.keyText) || int(nextWords) > len(d.keyWords)/4 {
+			return errCorruptedData
+		}
+		key := string(d.keyText[text:nextText])
+		if i > 0 && key <= prev {
+			return errCorruptedData
+		}
+		prev = key
+	}
+	for i := 0; i < len(d.keyWords); i += 4 {
+		if binary.LittleEndian.Uint32(d.keyWords[i:]) >= uint32(d.words) {
+			return errCorruptedData
+		}
+	}
+	return nil
+}
+
+func hasDAWGMagic(r *bufio.Reader) bool {
+	magic, _ := r.Peek(len(dawgMagic))
+	return string(magic) == dawgMagic
+}
+
+// Close unmaps the DAWG
+func (d *DAWG) Close() error {
+	if d.unmap == nil {
+		return nil
+	}
+	unmap := d.unmap
+	d.unmap = nil
+	return unmap()
+}
+
+// Len is the number of words in the DAWG
+func (d *DAWG) Len() int {
+	return d.words
+}
+
+// States is the number of states of the automaton
+func (d *DAWG) States() int {
+	return int(d.stateCount)
+}
+
+// Lookup returns the frequency of word and whether the DAWG holds it
+func (d *DAWG) Lookup(word string) (int, bool) {
+	s, index, ok := d.walk(word)
+	if !ok {
+		return 0, false
+	}
+	if _, _, final, _ := d.state(s); !final {
+		return 0, false
+	}
+	return d.frequency(index), true
+}
+
+func (d *DAWG) state(s uint32) (first, count uint32, final bool, words uint32) {
+	b := d.states[s*dawgStateSize:]
+	n := binary.LittleEndian.Uint32(b[4:])
+	return binary.LittleEndian.Uint32(b), n &^ dawgFinal, n&dawgFinal != 0, binary.LittleEndian.Uint32(b[8:])
+}
+
+func (d *DAWG) wordsFrom(s uint32) uint32 {
+	return binary.LittleEndian.Uint32(d.states[s*dawgStateSize+8:])
+}
+
+func (d *DAWG) edge(i uint32) (rune, uint32) {
+	b := d.edges[i*dawgEdgeSize:]
+	return rune(binary.LittleEndian.Uint32(b)), binary.LittleEndian.Uint32(b[4:])
+}
+
+// key returns the offset of the i-th key in the key text and the index of its first word number
+func (d *DAWG) key(i uint32) (text, words uint32) {
+	b := d.keys[i*dawgKeySize:]
+	return binary.LittleEndian.Uint32(b), binary.LittleEndian.Uint32(b[4:])
+}
+
+// soundsLike calls fn for every word with the Metaphone key key, in alphabetical order
+func (d *DAWG) soundsLike(key string, fn func(string)) {
+	i := uint32(sort.Search(int(d.count), func(i int) bool {
+		text, _ := d.key(uint32(i))
+		end, _ := d.key(uint32(i) + 1)
+		return string(d.keyText[text:end]) >= key
+	}))
+	if i == d.count {
+		return
+	}
+	text, first := d.key(i)
+	end, last := d.key(i + 1)
+	if string(d.keyText[text:end]) != key {
+		return
+	}
+	for j := first; j < last; j++ {
+		fn(d.word(binary.LittleEndian.Uint32(d.keyWords[j*4:])))
+	}
+}
+
+// word returns the word numbered index
+func (d *DAWG) word(index uint32) string {
+	var word []rune
+	var s uint32
+next:
+	for {
+		first, count, final, _ := d.state(s)
+		if final {
+			if index == 0 {
+				return string(word)
+			}
+			index--
+		}
+		for i := first; i < first+count; i++ {
+			letter, target := d.edge(i)
+			if n := d.wordsFrom(target); index >= n {
+				index -= n
+				continue
+			}
+			word, s = append(word, letter), target
+			continue next
+		}
+		return string(word)
+	}
+}
+
+// frequency returns the frequency of the word numbered index
+func (d *DAWG) frequency(index uint32) int {
+	return int(int64(binary.LittleEndian.Uint64(d.freqs[index*8:])))
+}
+
+// child returns the state reached from s by ch and how many of the words s leads to
+// come before those of that state
+func (d *DAWG) child(s uint32, ch rune) (next, skipped uint32, ok bool) {
+	first, count, final, _ := d.state(s)
+	if final {
+		skipped = 1
+	}
+	for i := first; i < first+count; i++ {
+		letter, target := d.edge(i)
+		if letter == ch {
+			return target, skipped, true
+		}
+		if letter > ch {
+			break
+		}
+		skipped += d.wordsFrom(target)
+	}
+	return 0, 0, false
+}
+
+// walk returns the state reached by the letters of word and the number of the first
+// word from that state
+func (d *DAWG) walk(word string) (s, index uint32, ok bool) {
+	for _, ch := range word {
+		next, skipped, ok := d.child(s, ch)
+		if !ok {
+			return 0, 0, false
+		}
+		s, index = next, index+skipped
+	}
+	return s, index, true
+}
+
+// collect calls fn for every word from state s in alphabetical order, where index is
+// the number of the first of them
+func (d *DAWG) collect(s, index uint32, prefix []rune, fn func(string, int)) {
+	first, count, final, _ := d.state(s)
+	if final {
+		fn(string(prefix), d.frequency(index))
+		index++
+	}
+	for i := first; i < first+count; i++ {
+		letter, target := d.edge(i)
+		d.collect(target, index, append(prefix, letter), fn)
+		index += d.wordsFrom(target)
+	}
+}
+
+func (d *DAWG) complete(prefix string, fn func(string, int)) {
+	if s, index, ok := d.walk(prefix); ok {
+		d.collect(s, index, []rune(prefix), fn)
+	}
+}
+
+func (d *DAWG) prefixWords(runes []rune, fn func(int, int)) {
+	var s, index uint32
+	for j, ch := range runes {
+		next, skipped, ok := d.child(s, ch)
+		if !ok {
+			return
+		}
+		s, index = next, index+skipped
+		if _, _, final, _ := d.state(s); final {
+			fn(j+1, d.frequency(index))
+		}
+	}
+}
+
+// visitDAWG is visit for the state reached by ch, whose words are numbered from index
+func (s *trieSearch) visitDAWG(d *DAWG, state, index uint32, ch rune, prefix []rune) {
+	dist, minInRow := s.step(ch, prefix)
+	prefix = append(prefix, ch)
+	first, count, final, _ := d.state(state)
+	if final {
+		if dist <= s.maxDist {
+			s.fn(string(prefix), dist, d.frequency(index))
+		}
+		index++
+	}
+	if minInRow > s.maxDist {
+		return
+	}
+	for i := first; i < first+count; i++ {
+		letter, target := d.edge(i)
+		s.visitDAWG(d, target, index, letter, prefix)
+		index += d.wordsFrom(target)
+	}
+}
+
+// loadDAWG adds the words of a DAWG file to wt. An empty wt keeps the DAWG mapped and
+// reads its words from it; otherwise they are inserted one by one.
+func (wt *WordTrie) loadDAWG(filename string) (DictionaryInfo, error) {
+	info := DictionaryInfo{Name: filename}
+	d, err := OpenDAWG(filename)
+	if err != nil {
+		return info, err
+	}
+	info.Words = d.Len()
+	if wt.words > 0 || wt.dawg != nil {
+		d.collect(0, 0, nil, wt.Insert)
+		return info, d.Close()
+	}
+
+	wt.dawg, wt.Root = d, &LetterNode{}
+	wt.words, wt.totalFrequency = d.Len(), d.total
+	wt.phonetic, wt.symspell, wt.bktree = newPhoneticIndex(), nil, nil
+	wt.cache.clear()
+	return info, nil
+}
+
+// overlay calls fn for the words dawgWords reports and those under node, an overlay node
+// whose letters are prefix, in alphabetical order. A word in both has the overlay's
+// frequency. The overlay only holds words inserted after the DAWG was loaded, so its
+// words are gathered first.
+func (wt *WordTrie) overlay(node *LetterNode, prefix []rune, dawgWords func(func(string, int)), fn func(string, int)) {
+	var words []Candidate
+	if node != nil {
+		collectNode(node, prefix, func(word string, frequency int) {
+			words = append(words, Candidate{Word: word, Frequency: frequency})
+		})
+	}
+	dawgWords(func(word string, frequency int) {
+		for len(words) > 0 && words[0].Word < word {
+			fn(words[0].Word, words[0].Frequency)
+			words = words[1:]
+		}
+		if len(words) > 0 && words[0].Word == word {
+			frequency, words = words[0].Frequency, words[1:]
+		}
+		fn(word, frequency)
+	})
+	for _, w := range words {
+		fn(w.Word, w.Frequency)
+	}
+}
+
+// overlaidSink passes on the candidates of a DAWG search except those the overlay
+// holds, which the search of the overlay reports with their own frequency
+type overlaidSink struct {
+	candidateSink
+	wt *WordTrie
+}
+
+func (s overlaidSink) add(candidate string, dist, frequency int) {
+	if n := s.wt.find(candidate); n == nil || !n.IsWord {
+		s.candidateSink.add(candidate, dist, frequency)
+	}
+}
+
+func (s overlaidSink) fork() candidateSink {
+	return overlaidSink{s.candidateSink.fork(), s.wt}
+}
+
+func (s overlaidSink) merge(other candidateSink) {
+	s.candidateSink.merge(other.(overlaidSink).candidateSink)
+}
+
+// searchDAWG is searchTrie over a DAWG, whose start state's edges stand in for the
+// subtrees of the root
+func (wt *WordTrie) searchDAWG(word string, maxDist int, transpositions bool, sink candidateSink) {
+	d := wt.dawg
+	first, count, final, _ := d.state(0)
+	// firstWord[i] numbers the first word behind the i-th edge
+	firstWord := make([]uint32, count)
+	next := uint32(0)
+	if final {
+		next = 1
+	}
+	for i := range firstWord {
+		_, target := d.edge(first + uint32(i))
+		firstWord[i] = next
+		next += d.wordsFrom(target)
+	}
+	visit := func(s *trieSearch, i int, prefix []rune) {
+		letter, target := d.edge(first + uint32(i))
+		s.visitDAWG(d, target, firstWord[i], letter, prefix)
+	}
+	if wt.workers > 1 && count > 1 {
+		wt.searchParallel(word, maxDist, transpositions, int(count), visit, sink)
+		return
+	}
+
+	s := newTrieSearch(word, maxDist, transpositions, sink.add)
+	prefix := make([]rune, 0, len(s.target)+maxDist)
+	for i := range int(count) {
+		visit(s, i, prefix)
+	}
+}
+
+// WriteDAWG writes the words of wt as a DAWG that OpenDAWG can map
+func (wt *WordTrie) WriteDAWG(w io.Writer) error {
+	root := wt.Root
+	if wt.dawg != nil {
+		if len(root.Children) == 0 && !root.IsWord {
+			_, err := w.Write(wt.dawg.data)
+			return err
+		}
+		t := NewWordTrie()
+		wt.collectWords(t.Insert)
+		root = t.Root
+	}
+
+	b := &dawgBuilder{ids: make(map[string]uint32)}
+	b.add(root)
+
+	le := binary.LittleEndian
+	n := uint32(len(b.states))
+	words := b.states[n-1].words
+	body := make([]byte, 0, int(n)*dawgStateSize+b.edges*dawgEdgeSize+int(words)*12)
+	// States were numbered after their targets; numbering them backwards puts the
+	// start first and every target after its source
+	edge := uint32(0)
+	for i := n; i > 0; i-- {
+		st := b.states[i-1]
+		count := uint32(len(st.edges))
+		if st.final {
+			count |= dawgFinal
+		}
+		body = le.AppendUint32(body, edge)
+		body = le.AppendUint32(body, count)
+		body = le.AppendUint32(body, st.words)
+		edge += uint32(len(st.edges))
+	}
+	for i := n; i > 0; i-- {
+		for _, e := range b.states[i-1].edges {
+			body = le.AppendUint32(body, uint32(e.letter))
+			body = le.AppendUint32(body, n-1-e.target)
+		}
+	}
+
+	// keyWords numbers the words of every Metaphone key
+	keyWords := make(map[string][]uint32)
+	index, total := uint32(0), 0
+	collectNode(root, nil, func(word string, frequency int) {
+		body = le.AppendUint64(body, uint64(int64(frequency)))
+		if key := phonetic.Metaphone(word); key != "" {
+			keyWords[key] = append(keyWords[key], index)
+		}
+		index++
+		total += frequency
+	})
+	keys := slices.Sorted(maps.Keys(keyWords))
+	var text, first uint32
+	for _, key := range keys {
+		body = le.AppendUint32(body, text)
+		body = le.AppendUint32(body, first)
+		text, first = text+uint32(len(key)), first+uint32(len(keyWords[key]))
+	}
+	body = le.AppendUint32(body, text)
+	body = le.AppendUint32(body, first)
+	for _, key := range keys {
+		for _, i := range keyWords[key] {
+			body = le.AppendUint32(body, i)
+		}
+	}
+	for _, key := range keys {
+		body = append(body, key...)
+	}
+
+	header := make([]byte, 0, dawgHeaderSize)
+	header = append(header, dawgMagic...)
+	header = le.AppendUint32(header, DAWGVersion)
+	header = le.AppendUint32(header, crc32.Checksum(body, snapshotTable))
+	header = le.AppendUint32(header, n)
+	header = le.AppendUint32(header, uint32(b.edges))
+	header = le.AppendUint32(header, words)
+	header = le.AppendUint32(header, uint32(len(keys)))
+	header = le.AppendUint64(header, uint64(int64(total)))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err := w.Write(body)
+	return err
+}
+
+// dawgBuilder minimizes a trie by giving identical subtrees a single state. States are
+// numbered in post-order, so every target has a lower number than its source.
+type dawgBuilder struct {
+	// ids maps the signature of a state, its word flag and edges, to its number
+	ids    map[string]uint32
+	states []dawgState
+	edges  int
+}
+
+type dawgState struct {
+	final bool
+	words uint32
+	edges []dawgEdge
+}
+
+type dawgEdge struct {
+	letter rune
+	target uint32
+}
+
+func (b *dawgBuilder) add(n *LetterNode) uint32 {
+	st := dawgState{final: n.IsWord, edges: make([]dawgEdge, len(n.Children))}
+	signature := make([]byte, 1, 1+len(n.Children)*8)
+	if n.IsWord {
+		st.words, signature[0] = 1, 1
+	}
+	for i, child := range n.Children {
+		id := b.add(child)
+		st.edges[i] = dawgEdge{child.Letter, id}
+		st.words += b.states[id].words
+		signature = binary.AppendUvarint(signature, uint64(child.Letter))
+		signature = binary.AppendUvarint(signature, uint64(id))
+	}
+
+	if id, ok := b.ids[string(signature)]; ok {
+		return id
+	}
+	id := uint32(len(b.states))
+	b.ids[string(signature)] = id
+	b.states = append(b.states, st)
+	b.edges += len(st.edges)
+	return id
+}
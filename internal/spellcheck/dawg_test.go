@@ -0,0 +1,122 @@
+package spellcheck
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// stackedWords changes the frequency of dictionary words and adds new ones, some of
+// which extend or prefix words of the dictionary
+const stackedWords = "the,5\nreceive,99999999\nfrobnicate,10\nthex,3\nrecei,2\nkubelet\n"
+
+func writeDAWG(t *testing.T, wt *WordTrie) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "words.dawg")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = wt.WriteDAWG(file); err != nil {
+		t.Fatal(err)
+	}
+	if err = file.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func loadDAWGFile(t *testing.T, path string) *WordTrie {
+	t.Helper()
+	wt := NewWordTrie()
+	if _, err := wt.LoadDictionary(path, false); err != nil {
+		t.Fatal(err)
+	}
+	if wt.dawg == nil {
+		t.Fatal("the DAWG was not kept mapped")
+	}
+	t.Cleanup(func() { _ = wt.dawg.Close() })
+	return wt
+}
+
+func TestDAWGRoundTrip(t *testing.T) {
+	wt := testTrie(t)
+	sameDictionary(t, wt, loadDAWGFile(t, writeDAWG(t, wt)))
+}
+
+func TestDAWGOverlay(t *testing.T) {
+	want, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := loadDAWGFile(t, writeDAWG(t, want))
+	for _, wt := range []*WordTrie{want, got} {
+		if _, err = wt.LoadWords("stacked.txt", strings.NewReader(stackedWords), false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got.dawg == nil {
+		t.Fatal("stacking words thawed the DAWG")
+	}
+	sameDictionary(t, want, got)
+
+	// Words stacked on a DAWG are compiled into the next one
+	sameDictionary(t, want, loadDAWGFile(t, writeDAWG(t, got)))
+
+	got.SetWorkers(4)
+	for _, word := range []string{"frobnicat", "recieve", "thw"} {
+		if got, want := got.FindCandidates(word, 2, 10), want.FindCandidates(word, 2, 10); !slices.Equal(got, want) {
+			t.Errorf("FindCandidates(%q) with 4 workers = %v, want %v", word, got, want)
+		}
+	}
+}
+
+func FuzzNewDAWG(f *testing.F) {
+	wt := NewWordTrie()
+	if _, err := wt.LoadWords("small.txt", strings.NewReader(smallDictionary), false); err != nil {
+		f.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := wt.WriteDAWG(&buf); err != nil {
+		f.Fatal(err)
+	}
+	f.Add(buf.Bytes())
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Fix up the version and checksum so that the structure checks are reached
+		if len(data) >= dawgHeaderSize {
+			data = bytes.Clone(data)
+			binary.LittleEndian.PutUint32(data[8:], DAWGVersion)
+			binary.LittleEndian.PutUint32(data[12:], crc32.Checksum(data[dawgHeaderSize:], snapshotTable))
+		}
+		d, err := newDAWG(data)
+		if err != nil {
+			return
+		}
+		// An accepted DAWG must be safe to walk in every way the spell checker does
+		index := uint32(0)
+		d.collect(0, 0, nil, func(word string, frequency int) {
+			if got, ok := d.Lookup(word); !ok || got != frequency {
+				t.Errorf("Lookup(%q) = %d, %v, want %d", word, got, ok, frequency)
+			}
+			if got := d.word(index); got != word {
+				t.Errorf("word(%d) = %q, want %q", index, got, word)
+			}
+			d.prefixWords([]rune(word), func(int, int) {})
+			index++
+		})
+		if int(index) != d.Len() {
+			t.Errorf("collected %d words, Len is %d", index, d.Len())
+		}
+		for i := range d.count {
+			text, _ := d.key(i)
+			end, _ := d.key(i + 1)
+			d.soundsLike(string(d.keyText[text:end]), func(string) {})
+		}
+	})
+}
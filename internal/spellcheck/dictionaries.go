@@ -148,3 +148,43 @@ var commonMisspellings = map[string]string{
 	"souveneir":    "souvenir",
 	"heroe":        "hero",
 }
+
+// defaultConfusionSets groups valid words that are commonly written for one another
+var defaultConfusionSets = [][]string{
+	{"their", "there", "they're"},
+	{"to", "too", "two"},
+	{"your", "you're"},
+	{"its", "it's"},
+	{"whose", "who's"},
+	{"affect", "effect"},
+	{"accept", "except"},
+	{"then", "than"},
+	{"lose", "loose"},
+	{"weather", "whether"},
+	{"peace", "piece"},
+	{"principal", "principle"},
+	{"advice", "advise"},
+	{"quiet", "quite"},
+	{"passed", "past"},
+	{"brake", "break"},
+	{"hear", "here"},
+	{"know", "no"},
+	{"were", "where", "we're"},
+}
+
+// defaultConfusionRules settle the most common confusions from a single neighbour. A rule
+// only ever replaces its word, so "have" is never taken for "of".
+var defaultConfusionRules = []ConfusionRule{
+	{Word: "too", Replacement: "to", Before: []string{"the", "a", "an", "my", "your", "his", "her", "its", "our", "their", "this", "that", "these", "those", "be", "go", "see", "get", "make", "do"}},
+	{Word: "two", Replacement: "to", Before: []string{"the", "a", "an", "my", "your", "his", "her", "its", "our", "their", "this", "be", "go", "see", "get", "make", "do"}},
+	{Word: "their", Replacement: "they're", Before: []string{"going", "coming", "not", "being", "doing", "getting", "trying", "making"}},
+	{Word: "there", Replacement: "they're", Before: []string{"going", "coming", "being", "doing", "getting", "trying", "making"}},
+	{Word: "their", Replacement: "there", Before: []string{"is", "are", "was", "were", "has", "have"}},
+	{Word: "your", Replacement: "you're", Before: []string{"going", "coming", "not", "being", "welcome", "doing", "always", "never"}},
+	{Word: "its", Replacement: "it's", Before: []string{"a", "an", "the", "not", "been", "going", "so", "too", "time"}},
+	{Word: "then", Replacement: "than", After: []string{"more", "less", "better", "worse", "rather", "other", "bigger", "smaller", "larger", "greater", "fewer", "higher", "lower", "older", "younger", "faster", "slower", "taller", "shorter", "longer", "stronger", "easier", "harder", "cheaper", "earlier", "later", "smarter"}},
+	{Word: "loose", Replacement: "lose", After: []string{"to", "will", "would", "could", "might", "may", "must", "don't", "didn't"}},
+	{Word: "effect", Replacement: "affect", After: []string{"will", "would", "could", "might", "may", "doesn't", "didn't"}},
+	{Word: "affect", Replacement: "effect", After: []string{"the", "an", "no", "any", "side", "positive", "negative"}},
+	{Word: "of", Replacement: "have", After: []string{"could", "would", "should", "must", "might"}},
+}
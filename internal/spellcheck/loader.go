@@ -2,36 +2,217 @@ package spellcheck
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"spellio/resources"
 	"strconv"
 	"strings"
 )
 
+// DefaultWordFrequency is the frequency of a dictionary word listed without one
+const DefaultWordFrequency = 1
+
+// DictionaryInfo describes a loaded dictionary: the metadata of its header and what
+// loading it found
+type DictionaryInfo struct {
+	// Name is the file the dictionary was read from
+	Name                      string
+	Language, Version, Source string
+	// Words is the number of distinct words the dictionary added or changed the frequency of
+	Words int
+	// Skipped holds a file:line error for every malformed line a lenient load left out
+	Skipped []error
+}
+
+// New returns a WordTrie holding the dictionary compiled into the binary
 func New() (*WordTrie, error) {
 	wt := NewWordTrie()
-	if err := wt.loadWords("resources/english_words_freqs.txt"); err != nil {
-		return nil, fmt.Errorf("failed to load words: %w", err)
+	if _, err := wt.LoadDefaultDictionary(); err != nil {
+		return nil, err
 	}
 	return wt, nil
 }
 
-func (wt *WordTrie) loadWords(filename string) error {
+// LoadDefaultDictionary adds the words of the dictionary compiled into the binary
+func (wt *WordTrie) LoadDefaultDictionary() (DictionaryInfo, error) {
+	info, err := wt.LoadWords("english_words_freqs.txt", bytes.NewReader(resources.EnglishWords), false)
+	if err != nil {
+		return info, fmt.Errorf("failed to load words: %w", err)
+	}
+	return info, nil
+}
+
+// LoadDictionary adds the words of a dictionary file to wt, see LoadWords, of a
+// snapshot written by WriteSnapshot or of a DAWG written by WriteDAWG. Words already in
+// wt take the frequency from the file.
+func (wt *WordTrie) LoadDictionary(filename string, lenient bool) (DictionaryInfo, error) {
 	file, err := os.Open(filename)
 	if err != nil {
-		return err
+		return DictionaryInfo{Name: filename}, err
 	}
 	defer func() { _ = file.Close() }()
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		pair := strings.Split(strings.TrimSpace(scanner.Text()), ",")
-		word := pair[0]
-		frequency, _ := strconv.Atoi(pair[1])
+	r := bufio.NewReader(file)
+	if hasDAWGMagic(r) {
+		return wt.loadDAWG(filename)
+	}
+	if !hasSnapshotMagic(r) {
+		return wt.LoadWords(filename, r, lenient)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return DictionaryInfo{Name: filename}, err
+	}
+	return wt.loadSnapshot(filename, data)
+}
+
+// LoadWords adds the words of a dictionary read from r to wt. Each line holds a word and
+// its frequency separated by a comma, or a bare word of DefaultWordFrequency; either
+// field may be quoted, with "" for a quote inside it. Blank lines and lines starting with
+// # are ignored, except that comments before the first word may set the language,
+// version and source of the dictionary:
+//
+//	# language: en
+//	# source: https://example.org/wordlist
+//	the,23135851162
+//	"rock 'n' roll",120540
+//	spellio
+//
+// A malformed line fails the load with a name:line error, or with lenient is skipped
+// and recorded in the returned Skipped.
+func (wt *WordTrie) LoadWords(name string, r io.Reader, lenient bool) (DictionaryInfo, error) {
+	info := DictionaryInfo{Name: name}
+	header := true
+	// changed holds the words counted in info.Words, so that a repeated word counts once
+	changed := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		if comment, ok := strings.CutPrefix(text, "#"); ok {
+			if header {
+				info.setMetadata(comment)
+			}
+			continue
+		}
+		header = false
+
+		word, frequency, err := parseDictionaryLine(text)
+		if err != nil {
+			err = fmt.Errorf("%s:%d: %w", name, line, err)
+			if !lenient {
+				return info, err
+			}
+			info.Skipped = append(info.Skipped, err)
+			continue
+		}
+		if current, ok := wt.lookup(word); ok && current == frequency {
+			continue
+		}
+		wt.Insert(word, frequency)
+		if !changed[word] {
+			changed[word] = true
+			info.Words++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return info, fmt.Errorf("%s: %w", name, err)
+	}
+	return info, nil
+}
+
+// setMetadata reads a "key: value" header comment; other comments are left alone
+func (info *DictionaryInfo) setMetadata(comment string) {
+	key, value, ok := strings.Cut(comment, ":")
+	if !ok {
+		return
+	}
+	value = strings.TrimSpace(value)
+	switch strings.ToLower(strings.TrimSpace(key)) {
+	case "language":
+		info.Language = value
+	case "version":
+		info.Version = value
+	case "source":
+		info.Source = value
+	}
+}
 
-		if word != "" {
-			wt.Insert(strings.ToLower(word), frequency)
+func parseDictionaryLine(text string) (string, int, error) {
+	fields, err := splitFields(text)
+	if err != nil {
+		return "", 0, err
+	}
+	if len(fields) > 2 {
+		return "", 0, fmt.Errorf("expected a word and its frequency, got %d fields", len(fields))
+	}
+	word := strings.ToLower(fields[0])
+	if word == "" {
+		return "", 0, errors.New("missing word")
+	}
+	if len(fields) == 1 {
+		return word, DefaultWordFrequency, nil
+	}
+	frequency, err := strconv.Atoi(fields[1])
+	if err != nil || frequency < 0 {
+		return "", 0, fmt.Errorf("invalid frequency %q for %q", fields[1], fields[0])
+	}
+	return word, frequency, nil
+}
+
+// splitFields splits a line on commas, trimming the space around each field. A field
+// may be quoted to hold commas, with "" standing for a quote.
+func splitFields(text string) ([]string, error) {
+	if !strings.Contains(text, `"`) {
+		fields := strings.Split(text, ",")
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		return fields, nil
+	}
+
+	var fields []string
+	for {
+		text = strings.TrimLeft(text, " \t")
+		var field string
+		if quoted, ok := strings.CutPrefix(text, `"`); ok {
+			var sb strings.Builder
+			for {
+				end := strings.IndexByte(quoted, '"')
+				if end < 0 {
+					return nil, errors.New("unterminated quoted field")
+				}
+				sb.WriteString(quoted[:end])
+				quoted = quoted[end+1:]
+				if !strings.HasPrefix(quoted, `"`) {
+					break
+				}
+				sb.WriteByte('"')
+				quoted = quoted[1:]
+			}
+			field, text = sb.String(), strings.TrimLeft(quoted, " \t")
+			if text != "" && text[0] != ',' {
+				return nil, fmt.Errorf("unexpected %q after quoted field", text)
+			}
+		} else {
+			end := strings.IndexByte(text, ',')
+			if end < 0 {
+				end = len(text)
+			}
+			field, text = strings.TrimSpace(text[:end]), text[end:]
+			if strings.Contains(field, `"`) {
+				return nil, fmt.Errorf("quote inside unquoted field %q", field)
+			}
+		}
+		fields = append(fields, field)
+		if text == "" {
+			return fields, nil
 		}
+		text = text[1:]
 	}
-	return scanner.Err()
 }
@@ -0,0 +1,154 @@
+package spellcheck
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSplitFields(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    []string
+		wantErr string
+	}{
+		{text: "the,100", want: []string{"the", "100"}},
+		{text: "  the ,  100 ", want: []string{"the", "100"}},
+		{text: "spellio", want: []string{"spellio"}},
+		{text: `"rock 'n' roll",120540`, want: []string{"rock 'n' roll", "120540"}},
+		{text: `"a, b", 5`, want: []string{"a, b", "5"}},
+		{text: `"say ""cheese""",3`, want: []string{`say "cheese"`, "3"}},
+		{text: `word,"7"`, want: []string{"word", "7"}},
+		{text: `""`, want: []string{""}},
+		{text: "a,b,c", want: []string{"a", "b", "c"}},
+		{text: `"open,1`, wantErr: "unterminated quoted field"},
+		{text: `"closed"x,1`, wantErr: `unexpected "x,1" after quoted field`},
+		{text: `it"s,1`, wantErr: "quote inside unquoted field"},
+	}
+	for _, tt := range tests {
+		got, err := splitFields(tt.text)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("splitFields(%q): %v", tt.text, err)
+		case tt.wantErr == "" && !slices.Equal(got, tt.want):
+			t.Errorf("splitFields(%q) = %q, want %q", tt.text, got, tt.want)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("splitFields(%q) error = %v, want %q", tt.text, err, tt.wantErr)
+		}
+	}
+}
+
+func TestLoadWords(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		lenient bool
+		want    map[string]int
+		info    DictionaryInfo
+		wantErr string
+		skipped []string
+	}{
+		{
+			name:  "header",
+			input: "# language: en\n# Version: 2024.1\n# source: https://example.org/words\n# a note\nthe,5\n# language: fr\n",
+			want:  map[string]int{"the": 5},
+			info:  DictionaryInfo{Language: "en", Version: "2024.1", Source: "https://example.org/words", Words: 1},
+		},
+		{
+			name:  "bare words and blank lines",
+			input: "\nSpellio\n\n  kubelet  \n",
+			want:  map[string]int{"spellio": DefaultWordFrequency, "kubelet": DefaultWordFrequency},
+			info:  DictionaryInfo{Words: 2},
+		},
+		{
+			name:  "quoted",
+			input: "\"rock 'n' roll\",120540\n\"a, b\",3\n",
+			want:  map[string]int{"rock 'n' roll": 120540, "a, b": 3},
+			info:  DictionaryInfo{Words: 2},
+		},
+		{
+			name:    "invalid frequency",
+			input:   "the,5\nadaeze,12k\n",
+			wantErr: `words.txt:2: invalid frequency "12k" for "adaeze"`,
+		},
+		{
+			name:    "too many fields",
+			input:   "# comment\n\na,1,2\n",
+			wantErr: "words.txt:3: expected a word and its frequency, got 3 fields",
+		},
+		{
+			name:    "missing word",
+			input:   ",5\n",
+			wantErr: "words.txt:1: missing word",
+		},
+		{
+			name:    "negative frequency",
+			input:   "the,-1\n",
+			wantErr: `words.txt:1: invalid frequency "-1" for "the"`,
+		},
+		{
+			name:    "lenient",
+			input:   "the,5\n\"open,1\nof,x\nand,2\n",
+			lenient: true,
+			want:    map[string]int{"the": 5, "and": 2},
+			info:    DictionaryInfo{Words: 2},
+			skipped: []string{"words.txt:2: unterminated quoted field", `words.txt:3: invalid frequency "x" for "of"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wt := NewWordTrie()
+			info, err := wt.LoadWords("words.txt", strings.NewReader(tt.input), tt.lenient)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var skipped []string
+			for _, err := range info.Skipped {
+				skipped = append(skipped, err.Error())
+			}
+			if !slices.Equal(skipped, tt.skipped) {
+				t.Errorf("Skipped = %q, want %q", skipped, tt.skipped)
+			}
+			tt.info.Name, tt.info.Skipped = "words.txt", info.Skipped
+			if !reflect.DeepEqual(info, tt.info) {
+				t.Errorf("info = %+v, want %+v", info, tt.info)
+			}
+			got := make(map[string]int)
+			wt.collectWords(func(word string, frequency int) { got[word] = frequency })
+			if len(got) != len(tt.want) {
+				t.Errorf("words = %v, want %v", got, tt.want)
+			}
+			for word, frequency := range tt.want {
+				if got[word] != frequency {
+					t.Errorf("frequency of %q = %d, want %d", word, got[word], frequency)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadWordsCountsChangedWords(t *testing.T) {
+	wt := NewWordTrie()
+	if _, err := wt.LoadWords("base.txt", strings.NewReader("the,100\nof,50\n"), false); err != nil {
+		t.Fatal(err)
+	}
+	// "the" is unchanged, "of" changes, "and" is new and repeated, "cat" is new
+	info, err := wt.LoadWords("top.txt", strings.NewReader("the,100\nof,60\nand,5\nand,7\ncat\n"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Words != 3 {
+		t.Errorf("Words = %d, want 3", info.Words)
+	}
+	if got := wt.GetWordFrequency("and"); got != 7 {
+		t.Errorf("GetWordFrequency(and) = %d, want 7", got)
+	}
+}
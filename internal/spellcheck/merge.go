@@ -0,0 +1,78 @@
+package spellcheck
+
+import (
+	"math"
+	"strings"
+)
+
+const (
+	// maxMergeTokens is the most tokens a split word is joined from
+	maxMergeTokens = 3
+	// mergeMargin is how much more frequent, in log10 units, a word must be than its
+	// parts as a phrase before valid words such as "some thing" are joined
+	mergeMargin = 3.0
+)
+
+// findMerges looks for runs of adjacent tokens, separated only by spaces, that form a
+// single word: always when one of them is not a word, as in "beau tiful", and otherwise
+// only for two words whose joined word is far more frequent than the phrase. It returns the
+// corrections and the tokens with every merged run replaced by one token for the word.
+func (wt *WordTrie) findMerges(sentence string, tokens []Token) ([]WordCorrection, []Token) {
+	var merges []WordCorrection
+	var merged []Token
+	for i := 0; i < len(tokens); i++ {
+		k := maxMergeTokens
+		for ; k >= 2; k-- {
+			if i+k <= len(tokens) && wt.shouldMerge(sentence, tokens[i:i+k]) {
+				break
+			}
+		}
+		if k < 2 {
+			merged = append(merged, tokens[i])
+			continue
+		}
+
+		run := tokens[i : i+k]
+		span := Token{Text: sentence[run[0].Start:run[k-1].End], Start: run[0].Start, End: run[k-1].End}
+		word := wt.preserveCase(run[0].Text, strings.ToLower(joinTokens(run)))
+		merges = append(merges, WordCorrection{Token: span, Word: word, Found: true, Kind: SplitWord})
+		merged = append(merged, Token{Text: word, Start: span.Start, End: span.End})
+		i += k - 1
+	}
+	return merges, merged
+}
+
+func (wt *WordTrie) shouldMerge(sentence string, run []Token) bool {
+	for i := 1; i < len(run); i++ {
+		if strings.TrimSpace(sentence[run[i-1].End:run[i].Start]) != "" {
+			return false
+		}
+	}
+	word := strings.ToLower(joinTokens(run))
+	frequency := wt.GetWordFrequency(word)
+	if frequency == 0 || !wt.IsWord(word) {
+		return false
+	}
+
+	parts := make([]string, len(run))
+	for i, t := range run {
+		if !wt.IsWord(t.Text) {
+			return true
+		}
+		parts[i] = strings.ToLower(t.Text)
+	}
+	// Three valid words, such as "to get her", are far more often a phrase than a split word
+	if len(run) > 2 {
+		return false
+	}
+	phrase := wt.phraseFrequency(strings.Join(parts, " "))
+	return math.Log10(float64(frequency)) >= math.Log10(float64(phrase+1))+mergeMargin
+}
+
+func joinTokens(run []Token) string {
+	var sb strings.Builder
+	for _, t := range run {
+		sb.WriteString(t.Text)
+	}
+	return sb.String()
+}
@@ -0,0 +1,62 @@
+package spellcheck
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMergedWordIsNotARealWordError(t *testing.T) {
+	wt, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt.SetConfusionSets(DefaultConfusionSets())
+	sentence := "thei r going home"
+	corrections := wt.CorrectSentence(sentence)
+	end := 0
+	for _, c := range corrections {
+		if c.Start < end {
+			t.Errorf("%s %q -> %q overlaps the correction before it", c.Kind, c.Text, c.Word)
+		}
+		end = c.End
+	}
+	if len(corrections) == 0 || corrections[0].Kind != SplitWord || corrections[0].Text != "thei r" {
+		t.Errorf("corrections = %+v, want %q merged first", corrections, "thei r")
+	}
+}
+
+func TestFindMerges(t *testing.T) {
+	wt := testTrie(t)
+	tests := []struct {
+		sentence string
+		want     []string
+	}{
+		{"a beau tiful day", []string{"beau tiful->beautiful"}},
+		{"Beau tiful", []string{"Beau tiful->Beautiful"}},
+		{"some thing happened", []string{"some thing->something"}},
+		{"spel ling and beau tiful", []string{"spel ling->spelling", "beau tiful->beautiful"}},
+		// Valid words that form a valid phrase stay apart
+		{"it may be late", nil},
+		{"every one of them", nil},
+		{"I want to get her a gift", nil},
+		{"all right", nil},
+		// Only spaces separate the parts of a split word
+		{"some, thing", nil},
+		{"beau-tiful", nil},
+	}
+	for _, tt := range tests {
+		merges, tokens := wt.findMerges(tt.sentence, Tokenize(tt.sentence))
+		var got []string
+		for _, m := range merges {
+			got = append(got, m.Text+"->"+m.Word)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("findMerges(%q) = %q, want %q", tt.sentence, got, tt.want)
+		}
+		for _, m := range merges {
+			if !slices.Contains(tokens, Token{Text: m.Word, Start: m.Start, End: m.End}) {
+				t.Errorf("findMerges(%q) left no token for %q", tt.sentence, m.Word)
+			}
+		}
+	}
+}
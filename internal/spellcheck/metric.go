@@ -0,0 +1,75 @@
+package spellcheck
+
+import (
+	"fmt"
+	"spellio/levenshtein"
+	"strings"
+)
+
+// Metric is a thresholded distance function from the levenshtein package. Scale is the
+// number of distance units a single edit costs, so limits can be expressed in edits, and
+// Transpositions reports whether swapping adjacent characters counts as one edit.
+type Metric struct {
+	Name           string
+	Distance       func(a, b string, threshold int) int
+	Scale          int
+	Transpositions bool
+}
+
+var (
+	LevenshteinMetric = Metric{Name: "levenshtein", Distance: levenshtein.BitParallelDistanceWithThreshold, Scale: 1}
+	OSAMetric         = Metric{Name: "osa", Distance: levenshtein.OSADistanceWithThreshold, Scale: 1, Transpositions: true}
+	DamerauMetric     = Metric{Name: "damerau", Distance: levenshtein.DamerauDistanceWithThreshold, Scale: 1, Transpositions: true}
+	KeyboardMetric    = Metric{Name: "keyboard", Distance: levenshtein.KeyboardAwareDistanceWithThreshold, Scale: 10}
+)
+
+// KeyboardMetricFor is KeyboardMetric with substitutions priced on layout
+func KeyboardMetricFor(layout *levenshtein.Layout) Metric {
+	if layout == levenshtein.QWERTY {
+		return KeyboardMetric
+	}
+	return Metric{Name: KeyboardMetric.Name, Distance: layout.DistanceWithThreshold, Scale: KeyboardMetric.Scale}
+}
+
+// Metrics lists the metrics a BK-tree can be keyed on. OSA is left out because it
+// violates the triangle inequality the tree relies on, and so is keyboard-aware
+// Damerau, whose cheaper adjacent-key substitutions cannot be combined with a swap.
+func Metrics() []Metric {
+	return []Metric{LevenshteinMetric, DamerauMetric, KeyboardMetric}
+}
+
+// Distances lists the metrics candidates can be measured and ranked with
+func Distances() []Metric {
+	return []Metric{LevenshteinMetric, OSAMetric}
+}
+
+func ParseMetric(name string) (Metric, error) {
+	return parseMetric(name, Metrics())
+}
+
+func ParseDistance(name string) (Metric, error) {
+	return parseMetric(name, Distances())
+}
+
+func parseMetric(name string, metrics []Metric) (Metric, error) {
+	for _, m := range metrics {
+		if strings.EqualFold(name, m.Name) {
+			return m, nil
+		}
+	}
+	return Metric{}, fmt.Errorf("unknown metric: %s", name)
+}
+
+// SetDistance selects the metric candidates are measured with; it must be one of Distances
+func (wt *WordTrie) SetDistance(m Metric) error {
+	if _, err := ParseDistance(m.Name); err != nil {
+		return err
+	}
+	wt.distance = m
+	wt.cache.clear()
+	return nil
+}
+
+func (wt *WordTrie) Distance() Metric {
+	return wt.distance
+}
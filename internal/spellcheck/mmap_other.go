@@ -0,0 +1,17 @@
+//go:build !linux
+
+package spellcheck
+
+import (
+	"io"
+	"os"
+)
+
+// mapFile reads the first size bytes of file into memory where mapping it is not supported
+func mapFile(file *os.File, size int) ([]byte, func() error, error) {
+	data := make([]byte, size)
+	if _, err := io.ReadFull(file, data); err != nil {
+		return nil, nil, err
+	}
+	return data, func() error { return nil }, nil
+}
@@ -0,0 +1,217 @@
+package spellcheck
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LanguageModel holds unigram, bigram and trigram counts and scores a word given the two
+// words before it with stupid backoff (Brants et al., 2007)
+type LanguageModel struct {
+	// counts is keyed by the words of each n-gram joined by single spaces
+	counts map[string]int
+	total  int
+	order  int
+}
+
+// Sentence boundary markers; a count file may list n-grams starting with <s> or
+// ending with </s> to model how sentences begin and end
+const (
+	sentenceStart = "<s>"
+	sentenceEnd   = "</s>"
+)
+
+const (
+	// backoffFactor discounts the score of a shorter n-gram when the longer one is unseen
+	backoffFactor = 0.4
+	// contextCandidates is the number of corrections considered for each misspelled word
+	contextCandidates = 5
+	// contextBeamWidth is the number of partial sentences kept after each word
+	contextBeamWidth = 10
+)
+
+func NewLanguageModel() *LanguageModel {
+	return &LanguageModel{counts: make(map[string]int)}
+}
+
+// Add counts an n-gram of one to three words
+func (lm *LanguageModel) Add(count int, words ...string) {
+	key := strings.ToLower(strings.Join(words, " "))
+	lm.counts[key] += count
+	if len(words) == 1 {
+		lm.total += count
+	}
+	lm.order = max(lm.order, len(words))
+}
+
+// Order is the length of the longest n-gram in the model
+func (lm *LanguageModel) Order() int {
+	return lm.order
+}
+
+// LoadLanguageModel reads an n-gram count file with one n-gram of one to three words
+// per line, followed by its count; blank lines and lines starting with # are ignored:
+//
+//	the 53097401
+//	of the 2593413
+//	<s> the 1043217
+//	one of the 245101
+func LoadLanguageModel(filename string) (*LanguageModel, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	lm := NewLanguageModel()
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) < 2 || len(fields) > 4 {
+			return nil, fmt.Errorf("%s:%d: expected one to three words and a count", filename, line)
+		}
+		count, err := strconv.Atoi(fields[len(fields)-1])
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("%s:%d: invalid count %q", filename, line, fields[len(fields)-1])
+		}
+		lm.Add(count, fields[:len(fields)-1]...)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lm, nil
+}
+
+// LogProb returns the log10 score of word following prev2 and prev1, either of which
+// may be empty. Unseen words get an add-one estimate so that every score is finite.
+func (lm *LanguageModel) LogProb(prev2, prev1, word string) float64 {
+	word = strings.ToLower(word)
+	penalty := 0.0
+	if prev2 != "" && prev1 != "" {
+		context := strings.ToLower(prev2 + " " + prev1)
+		if p, ok := lm.ratio(context+" "+word, context); ok {
+			return p
+		}
+		penalty += math.Log10(backoffFactor)
+	}
+	if prev1 != "" {
+		context := strings.ToLower(prev1)
+		if p, ok := lm.ratio(context+" "+word, context); ok {
+			return penalty + p
+		}
+		penalty += math.Log10(backoffFactor)
+	}
+	return penalty + math.Log10(float64(lm.counts[word]+1)/float64(lm.total+len(lm.counts)+1))
+}
+
+func (lm *LanguageModel) ratio(ngram, context string) (float64, bool) {
+	n, c := lm.counts[ngram], lm.counts[context]
+	if n == 0 || c == 0 {
+		return 0, false
+	}
+	return math.Log10(float64(n) / float64(c)), true
+}
+
+// SetLanguageModel makes CorrectSentence choose corrections that fit their context;
+// nil corrects every word on its own
+func (wt *WordTrie) SetLanguageModel(lm *LanguageModel) {
+	wt.lm = lm
+}
+
+func (wt *WordTrie) LanguageModel() *LanguageModel {
+	return wt.lm
+}
+
+// latticeOption is one word a token may stand for and its log10 error-model score
+type latticeOption struct {
+	word    string
+	found   bool
+	channel float64
+}
+
+type beamState struct {
+	prev2, prev1 string
+	score        float64
+	back         *beamState
+	option       int
+}
+
+// correctInContext runs a beam search over the lattice of candidates for every token,
+// scoring each partial sentence by language model and error model together
+func (wt *WordTrie) correctInContext(tokens []Token) []WordCorrection {
+	lattice := make([][]latticeOption, len(tokens))
+	misspelled := make([]bool, len(tokens))
+	for i, token := range tokens {
+		if wt.IsWord(token.Text) {
+			lattice[i] = []latticeOption{{word: token.Text, found: true}}
+			continue
+		}
+		misspelled[i] = true
+		for _, c := range wt.AutocorrectMultiple(token.Text, contextCandidates) {
+			lattice[i] = append(lattice[i], latticeOption{word: c.Word, found: true, channel: wt.contextChannel(c)})
+		}
+		if len(lattice[i]) == 0 {
+			lattice[i] = []latticeOption{{word: token.Text}}
+		}
+	}
+
+	beam := []*beamState{{prev1: sentenceStart}}
+	for _, options := range lattice {
+		var next []*beamState
+		for _, state := range beam {
+			for k, option := range options {
+				next = append(next, &beamState{
+					prev2:  state.prev1,
+					prev1:  option.word,
+					score:  state.score + option.channel + wt.lm.LogProb(state.prev2, state.prev1, option.word),
+					back:   state,
+					option: k,
+				})
+			}
+		}
+		sort.SliceStable(next, func(i, j int) bool { return next[i].score > next[j].score })
+		beam = next[:min(len(next), contextBeamWidth)]
+	}
+
+	best := beam[0]
+	if wt.lm.counts[sentenceEnd] > 0 {
+		for _, state := range beam {
+			state.score += wt.lm.LogProb(state.prev2, state.prev1, sentenceEnd)
+		}
+		for _, state := range beam[1:] {
+			if state.score > best.score {
+				best = state
+			}
+		}
+	}
+
+	chosen := make([]int, len(tokens))
+	for i, state := len(tokens)-1, best; i >= 0; i, state = i-1, state.back {
+		chosen[i] = state.option
+	}
+	var corrections []WordCorrection
+	for i, token := range tokens {
+		if misspelled[i] {
+			option := lattice[i][chosen[i]]
+			corrections = append(corrections, WordCorrection{Token: token, Word: option.word, Found: option.found})
+		}
+	}
+	return corrections
+}
+
+// contextChannel is the log10 error-model score of a candidate: the posterior of the
+// noisy-channel model when one is set, otherwise one order of magnitude per edit
+func (wt *WordTrie) contextChannel(c Correction) float64 {
+	if wt.channel != nil || c.Confidence >= 0.98 {
+		return math.Log10(max(c.Confidence, 1e-9))
+	}
+	return -rankDistance(float64(c.Distance), c.Phonetic, DefaultMaxDistance)
+}
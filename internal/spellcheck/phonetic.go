@@ -0,0 +1,75 @@
+package spellcheck
+
+import (
+	"sort"
+	"spellio/phonetic"
+)
+
+// phoneticBonus is the score component a candidate earns for sounding like the word,
+// in edits. Candidates found only by sound are ranked as one edit beyond the search
+// radius, however far apart they are spelled.
+const phoneticBonus = 1.0
+
+// minPhoneticKey is the number of sounds a key needs before it is specific enough to
+// add candidates to a correction; shorter keys such as "T" for "teh" are shared by thousands of words
+const minPhoneticKey = 3
+
+// phoneticIndex groups dictionary words by Metaphone key, so that sound-alike
+// misspellings too far away for the edit-distance search still find their word
+type phoneticIndex struct {
+	words map[string][]string
+}
+
+func newPhoneticIndex() *phoneticIndex {
+	return &phoneticIndex{words: make(map[string][]string)}
+}
+
+func (idx *phoneticIndex) add(word string) {
+	if key := phonetic.Metaphone(word); key != "" {
+		idx.words[key] = append(idx.words[key], word)
+	}
+}
+
+// SoundsLike returns the dictionary words sharing word's Metaphone key, most frequent
+// first, with their edit distance from word
+func (wt *WordTrie) SoundsLike(word string) []Candidate {
+	var candidates []Candidate
+	wt.soundsLike(word, 1, func(candidate string, dist, frequency int) {
+		candidates = append(candidates, Candidate{Word: candidate, Distance: dist, Frequency: frequency})
+	})
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Frequency == candidates[j].Frequency {
+			return candidates[i].Word < candidates[j].Word
+		}
+		return candidates[i].Frequency > candidates[j].Frequency
+	})
+	return candidates
+}
+
+// soundsLike calls fn for every word sharing word's key if it has at least minKey sounds
+func (wt *WordTrie) soundsLike(word string, minKey int, fn func(string, int, int)) {
+	key := phonetic.Metaphone(word)
+	if key == "" || len(key) < minKey {
+		return
+	}
+	report := func(candidate string) {
+		dist := wt.distance.Distance(word, candidate, -1) / wt.distance.Scale
+		fn(candidate, dist, wt.GetWordFrequency(candidate))
+	}
+	for _, candidate := range wt.phonetic.words[key] {
+		report(candidate)
+	}
+	if wt.dawg != nil {
+		wt.dawg.soundsLike(key, report)
+	}
+}
+
+// rankDistance is the distance in edits a correction is ranked with, capped just past
+// maxDist and less the phonetic bonus
+func rankDistance(dist float64, phonetic bool, maxDist int) float64 {
+	dist = min(dist, float64(maxDist+1))
+	if phonetic {
+		dist -= phoneticBonus
+	}
+	return dist
+}
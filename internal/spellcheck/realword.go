@@ -0,0 +1,203 @@
+package spellcheck
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// ConfusionSets lists groups of valid words that are easily mistaken for each other,
+// such as their/there/they're, and rules that pick the intended one from a neighbour
+type ConfusionSets struct {
+	// alternatives maps every word of a set to the other words of its set, and the word of
+	// every rule to its replacement
+	alternatives map[string][]string
+	rules        []ConfusionRule
+}
+
+// ConfusionRule replaces Word with Replacement when the word right before it is one of
+// After, or the word right after it is one of Before
+type ConfusionRule struct {
+	Word, Replacement string
+	Before, After     []string
+}
+
+// realWordMargin is how much more likely, in log10 units, the language model must find
+// another word of the set before the word in the text is flagged
+const realWordMargin = 1.0
+
+func NewConfusionSets() *ConfusionSets {
+	return &ConfusionSets{alternatives: make(map[string][]string)}
+}
+
+// DefaultConfusionSets returns the built-in sets and rules
+func DefaultConfusionSets() *ConfusionSets {
+	cs := NewConfusionSets()
+	for _, set := range defaultConfusionSets {
+		cs.AddSet(set...)
+	}
+	for _, rule := range defaultConfusionRules {
+		cs.AddRule(rule)
+	}
+	return cs
+}
+
+func (cs *ConfusionSets) AddSet(words ...string) {
+	for _, w := range words {
+		w = strings.ToLower(w)
+		for _, other := range words {
+			other = strings.ToLower(other)
+			if other != w && !slices.Contains(cs.alternatives[w], other) {
+				cs.alternatives[w] = append(cs.alternatives[w], other)
+			}
+		}
+	}
+}
+
+// AddRule adds a rule, and its replacement as an alternative of its word if it is not one
+// yet. The replacement does not get the word as an alternative in turn, since a rule such
+// as "of -> have" goes one way only.
+func (cs *ConfusionSets) AddRule(rule ConfusionRule) {
+	if !slices.Contains(cs.alternatives[rule.Word], rule.Replacement) {
+		cs.alternatives[rule.Word] = append(cs.alternatives[rule.Word], rule.Replacement)
+	}
+	cs.rules = append(cs.rules, rule)
+}
+
+// LoadConfusionSets reads a confusion file. Each line lists the words of one set, or a
+// rule of the form "wrong -> right before|after word..."; blank lines and lines starting
+// with # are ignored:
+//
+//	affect effect
+//	too -> to before the a an my your
+//	then -> than after more less better
+func LoadConfusionSets(filename string) (*ConfusionSets, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	cs := NewConfusionSets()
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(strings.ToLower(normalizeApostrophe(scanner.Text())))
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if !slices.Contains(fields, "->") {
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("%s:%d: a confusion set needs at least two words", filename, line)
+			}
+			cs.AddSet(fields...)
+			continue
+		}
+
+		if len(fields) < 5 || fields[1] != "->" {
+			return nil, fmt.Errorf("%s:%d: expected \"wrong -> right before|after word...\"", filename, line)
+		}
+		rule := ConfusionRule{Word: fields[0], Replacement: fields[2]}
+		switch fields[3] {
+		case "before":
+			rule.Before = fields[4:]
+		case "after":
+			rule.After = fields[4:]
+		default:
+			return nil, fmt.Errorf("%s:%d: unknown rule position %q", filename, line, fields[3])
+		}
+		cs.AddRule(rule)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	return cs, nil
+}
+
+// SetConfusionSets turns on real-word error detection in CorrectSentence; nil turns it off
+func (wt *WordTrie) SetConfusionSets(cs *ConfusionSets) {
+	wt.confusions = cs
+}
+
+func (wt *WordTrie) ConfusionSets() *ConfusionSets {
+	return wt.confusions
+}
+
+// detectRealWords flags valid words that are probably a mix-up with another word of their
+// confusion set. words holds the text of every token after spelling corrections, which
+// serves as the context. Rules are tried first; the language model decides otherwise.
+func (wt *WordTrie) detectRealWords(tokens []Token, words []string) []WordCorrection {
+	var findings []WordCorrection
+	for i, token := range tokens {
+		word := strings.ToLower(normalizeApostrophe(words[i]))
+		alternatives := wt.confusions.alternatives[word]
+		if len(alternatives) == 0 || !wt.IsWord(token.Text) {
+			continue
+		}
+
+		replacement := wt.confusions.applyRules(word, contextWord(words, i-1), contextWord(words, i+1))
+		if replacement == "" && wt.lm != nil {
+			replacement = wt.bestInContext(words, i, word, alternatives)
+		}
+		if replacement != "" {
+			findings = append(findings, WordCorrection{
+				Token: token,
+				Word:  wt.preserveCase(token.Text, replacement),
+				Found: true,
+				Kind:  RealWordError,
+			})
+		}
+	}
+	return findings
+}
+
+func (cs *ConfusionSets) applyRules(word, prev, next string) string {
+	for _, rule := range cs.rules {
+		if rule.Word != word {
+			continue
+		}
+		if slices.Contains(rule.After, prev) || slices.Contains(rule.Before, next) {
+			return rule.Replacement
+		}
+	}
+	return ""
+}
+
+// bestInContext returns the alternative the language model prefers over word by at least
+// realWordMargin, scoring every trigram that covers position i
+func (wt *WordTrie) bestInContext(words []string, i int, word string, alternatives []string) string {
+	score := func(candidate string) float64 {
+		prev2, prev1 := contextWord(words, i-2), contextWord(words, i-1)
+		next1, next2 := contextWord(words, i+1), contextWord(words, i+2)
+		switch i {
+		case 0:
+			prev1 = sentenceStart
+		case 1:
+			prev2 = sentenceStart
+		}
+		total := wt.lm.LogProb(prev2, prev1, candidate)
+		if next1 != "" {
+			total += wt.lm.LogProb(prev1, candidate, next1)
+		}
+		if next2 != "" {
+			total += wt.lm.LogProb(candidate, next1, next2)
+		}
+		return total
+	}
+
+	best, bestScore := "", score(word)+realWordMargin
+	for _, alternative := range alternatives {
+		if s := score(alternative); s > bestScore {
+			best, bestScore = alternative, s
+		}
+	}
+	return best
+}
+
+func contextWord(words []string, i int) string {
+	if i < 0 || i >= len(words) {
+		return ""
+	}
+	return strings.ToLower(normalizeApostrophe(words[i]))
+}
@@ -0,0 +1,86 @@
+package spellcheck
+
+import (
+	"testing"
+)
+
+func realWordFindings(wt *WordTrie, sentence string) []string {
+	var found []string
+	for _, c := range wt.CorrectSentence(sentence) {
+		if c.Kind == RealWordError {
+			found = append(found, c.Text+"->"+c.Word)
+		}
+	}
+	return found
+}
+
+func TestDefaultConfusionRules(t *testing.T) {
+	wt, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt.SetConfusionSets(DefaultConfusionSets())
+
+	// Correct English the rules used to flag
+	for _, sentence := range []string{
+		"Raise your right hand",
+		"It is your very own",
+		"That was your last chance",
+		"Is there not a better way",
+		"Their very own house",
+		"They were their always",
+		"We want to effect change",
+		"Laws can effect reform",
+		"We could have gone",
+		"They took it against their will",
+		"The two have met",
+		"The two that remained",
+		"I too have seen it",
+		"The knot is not loose",
+		"The knot was never loose",
+		"It hired its very best staff",
+	} {
+		if found := realWordFindings(wt, sentence); len(found) > 0 {
+			t.Errorf("%q: flagged %v", sentence, found)
+		}
+	}
+
+	tests := []struct {
+		sentence string
+		want     string
+	}{
+		{"I went too the store", "too->to"},
+		{"He is taller then me", "then->than"},
+		{"I think your going to win", "your->you're"},
+		{"Their going home", "Their->They're"},
+		{"It will effect everyone", "effect->affect"},
+		{"I could of been there", "of->have"},
+	}
+	for _, tt := range tests {
+		found := realWordFindings(wt, tt.sentence)
+		if len(found) != 1 || found[0] != tt.want {
+			t.Errorf("%q: flagged %v, want [%s]", tt.sentence, found, tt.want)
+		}
+	}
+}
+
+func TestRuleGoesOneWay(t *testing.T) {
+	wt, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt.SetConfusionSets(DefaultConfusionSets())
+	// A language model that only ever saw the misspelling must not undo the correct form
+	lm := NewLanguageModel()
+	for _, ngram := range [][]string{
+		{"we"}, {"could"}, {"of"}, {"gone"},
+		{"we", "could"}, {"could", "of"}, {"of", "gone"},
+		{"we", "could", "of"}, {"could", "of", "gone"},
+	} {
+		lm.Add(1000, ngram...)
+	}
+	wt.SetLanguageModel(lm)
+	if found := realWordFindings(wt, "We could have gone"); len(found) > 0 {
+		t.Errorf("flagged %v", found)
+	}
+}
@@ -0,0 +1,151 @@
+package spellcheck
+
+import (
+	"runtime"
+	"sync"
+	"unicode/utf8"
+)
+
+// trieSearch walks the trie computing one Levenshtein DP row per node against target.
+// Subtrees whose row minimum already exceeds maxDist cannot contain a match and are
+// pruned, so only the neighbourhood of the word is visited instead of the whole dictionary.
+// With transpositions the rows follow optimal string alignment, which looks one row
+// further back whenever the last two letters of the path are swapped in the word.
+type trieSearch struct {
+	target         []rune
+	maxDist        int
+	transpositions bool
+	fn             func(string, int, int)
+
+	// rows[d] holds the DP row for the node at depth d; rows are reused across siblings
+	rows [][]int
+}
+
+func newTrieSearch(word string, maxDist int, transpositions bool, fn func(string, int, int)) *trieSearch {
+	s := &trieSearch{target: []rune(word), maxDist: maxDist, transpositions: transpositions, fn: fn}
+	s.rows = [][]int{make([]int, len(s.target)+1)}
+	for i := range s.rows[0] {
+		s.rows[0][i] = i
+	}
+	return s
+}
+
+// visit computes the row of child, a child of the node at prefix, reports child if it is
+// a word within maxDist and searches its children unless the row prunes them
+func (s *trieSearch) visit(child *LetterNode, prefix []rune) {
+	dist, minInRow := s.step(child.Letter, prefix)
+	prefix = append(prefix, child.Letter)
+	if child.IsWord && dist <= s.maxDist {
+		s.fn(string(prefix), dist, child.Frequency)
+	}
+	if minInRow <= s.maxDist {
+		for _, grandchild := range child.Children {
+			s.visit(grandchild, prefix)
+		}
+	}
+}
+
+// step computes the row for prefix followed by ch and returns the distance of that path
+// to the word and the row minimum
+func (s *trieSearch) step(ch rune, prefix []rune) (dist, minInRow int) {
+	depth, cols := len(prefix), len(s.target)+1
+	if len(s.rows) <= depth+1 {
+		s.rows = append(s.rows, make([]int, cols))
+	}
+	prev, row := s.rows[depth], s.rows[depth+1]
+
+	row[0] = prev[0] + 1
+	minInRow = row[0]
+	for i := 1; i < cols; i++ {
+		cost := 0
+		if s.target[i-1] != ch {
+			cost = 1
+		}
+		row[i] = min(
+			prev[i]+1,      // deletion
+			row[i-1]+1,     // insertion
+			prev[i-1]+cost, // substitution
+		)
+		if s.transpositions && i > 1 && depth > 0 && s.target[i-1] == prefix[depth-1] && s.target[i-2] == ch {
+			row[i] = min(row[i], s.rows[depth-1][i-2]+1) // transposition
+		}
+		if row[i] < minInRow {
+			minInRow = row[i]
+		}
+	}
+	return row[cols-1], minInRow
+}
+
+// searchTrie reports every word within maxDist of word. With more than one worker the
+// subtrees of the root are searched in parallel, see searchParallel.
+func (wt *WordTrie) searchTrie(word string, maxDist int, transpositions bool, sink candidateSink) {
+	if frequency, ok := wt.lookup(""); ok {
+		if n := utf8.RuneCountInString(word); n <= maxDist {
+			sink.add("", n, frequency)
+		}
+	}
+	if wt.dawg != nil {
+		wt.searchDAWG(word, maxDist, transpositions, overlaidSink{sink, wt})
+	}
+	// With a DAWG, Root only holds the overlay of words inserted since it was loaded
+	subtrees := wt.Root.Children
+	visit := func(s *trieSearch, i int, prefix []rune) { s.visit(subtrees[i], prefix) }
+	if wt.workers > 1 && len(subtrees) > 1 {
+		wt.searchParallel(word, maxDist, transpositions, len(subtrees), visit, sink)
+		return
+	}
+
+	s := newTrieSearch(word, maxDist, transpositions, sink.add)
+	prefix := make([]rune, 0, len(s.target)+maxDist)
+	for i := range subtrees {
+		visit(s, i, prefix)
+	}
+}
+
+// searchParallel hands the subtrees of the root, which visit searches by index, to
+// wt.workers goroutines, each with its own DP rows. Every subtree is searched into its own
+// fork of sink, and the forks are merged into sink on the calling goroutine in letter order
+// once all workers are done, so sink needs no locking and the merge does not depend on
+// how the subtrees were scheduled.
+func (wt *WordTrie) searchParallel(word string, maxDist int, transpositions bool, subtrees int,
+	visit func(s *trieSearch, i int, prefix []rune), sink candidateSink) {
+	forks := make([]candidateSink, subtrees)
+	jobs := make(chan int, subtrees)
+	for i := range subtrees {
+		jobs <- i
+	}
+	close(jobs)
+
+	var wg sync.WaitGroup
+	for range min(wt.workers, subtrees) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s := newTrieSearch(word, maxDist, transpositions, nil)
+			prefix := make([]rune, 0, len(s.target)+maxDist)
+			for i := range jobs {
+				forks[i] = sink.fork()
+				s.fn = forks[i].add
+				visit(s, i, prefix)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, fork := range forks {
+		sink.merge(fork)
+	}
+}
+
+// SetWorkers sets the number of goroutines a trie search may use; 0 uses one per CPU
+// and 1 searches on the calling goroutine
+func (wt *WordTrie) SetWorkers(n int) {
+	if n <= 0 {
+		n = runtime.NumCPU()
+	}
+	wt.workers = n
+}
+
+func (wt *WordTrie) Workers() int {
+	return max(wt.workers, 1)
+}
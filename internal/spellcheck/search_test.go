@@ -0,0 +1,170 @@
+package spellcheck
+
+import (
+	"slices"
+	"spellio/levenshtein"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// document is a paragraph of the kind spellio checks, misspellings included
+const document = `Teh quick brwon fox jumps ovre the lazy dog. Wehn we recieve a
+mesage from our custmers we allways try to anwser it the same day, but somtimes
+the speling of the orignal request is so diferent from what we expect that it takes
+a littel longer. Definately beleive that a good spell checker makes the diference
+between a profesional reply and an embarassing one, espesially in a bussiness
+enviroment where evrything is writen down and kept for refrence.`
+
+var (
+	defaultTrieOnce sync.Once
+	defaultTrie     *WordTrie
+)
+
+// testTrie returns a WordTrie of the built-in dictionary, loaded once for all tests.
+// Tests must not change it.
+func testTrie(tb testing.TB) *WordTrie {
+	tb.Helper()
+	defaultTrieOnce.Do(func() {
+		wt, err := New()
+		if err != nil {
+			tb.Fatal(err)
+		}
+		defaultTrie = wt
+	})
+	if defaultTrie == nil {
+		tb.Fatal("built-in dictionary failed to load")
+	}
+	return defaultTrie
+}
+
+func documentWords() []string {
+	var words []string
+	for _, token := range Tokenize(document) {
+		words = append(words, strings.ToLower(token.Text))
+	}
+	return words
+}
+
+// scanCandidates is the search FindCandidates ran before the trie walk: every word of
+// the dictionary measured against word
+func scanCandidates(wt *WordTrie, word string, maxDist int, transpositions bool) []trieMatch {
+	distance := levenshtein.DistanceWithThreshold
+	if transpositions {
+		distance = levenshtein.OSADistanceWithThreshold
+	}
+	var matches []trieMatch
+	wt.collectWords(func(candidate string, frequency int) {
+		if dist := distance(word, candidate, maxDist); dist <= maxDist {
+			matches = append(matches, trieMatch{candidate, dist, frequency})
+		}
+	})
+	return matches
+}
+
+type trieMatch struct {
+	word      string
+	dist      int
+	frequency int
+}
+
+// matchList is a candidateSink that keeps every match, in the order of the search
+type matchList struct {
+	matches []trieMatch
+}
+
+func (l *matchList) add(candidate string, dist, frequency int) {
+	l.matches = append(l.matches, trieMatch{candidate, dist, frequency})
+}
+
+func (l *matchList) fork() candidateSink {
+	return &matchList{}
+}
+
+func (l *matchList) merge(other candidateSink) {
+	l.matches = append(l.matches, other.(*matchList).matches...)
+}
+
+func trieCandidates(wt *WordTrie, word string, maxDist int, transpositions bool) []trieMatch {
+	var list matchList
+	wt.searchTrie(word, maxDist, transpositions, &list)
+	return list.matches
+}
+
+func TestSearchTrieMatchesScan(t *testing.T) {
+	if testing.Short() {
+		t.Skip("scans the whole dictionary for every word")
+	}
+	wt := testTrie(t)
+	for _, word := range documentWords() {
+		for _, transpositions := range []bool{false, true} {
+			// Distances within the limit are exact, so one scan covers every smaller limit
+			scan := scanCandidates(wt, word, DefaultMaxDistance, transpositions)
+			for maxDist := 0; maxDist <= DefaultMaxDistance; maxDist++ {
+				want := slices.DeleteFunc(slices.Clone(scan), func(m trieMatch) bool { return m.dist > maxDist })
+				got := trieCandidates(wt, word, maxDist, transpositions)
+				if !slices.Equal(got, want) {
+					t.Errorf("searchTrie(%q, %d, %v) = %v, scan gives %v", word, maxDist, transpositions, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestParallelSearchMatchesSequential(t *testing.T) {
+	sequential, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parallel, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sequential.SetWorkers(1)
+	parallel.SetWorkers(4)
+
+	channel := NewChannelModel()
+	channel.Train("teh", "the")
+	channel.Train("recieve", "receive")
+	for _, model := range []*ChannelModel{nil, channel} {
+		sequential.SetChannelModel(model)
+		parallel.SetChannelModel(model)
+		for _, word := range documentWords() {
+			if got, want := parallel.FindCandidates(word, 2, 5), sequential.FindCandidates(word, 2, 5); !slices.Equal(got, want) {
+				t.Errorf("FindCandidates(%q) = %v with 4 workers, %v with 1", word, got, want)
+			}
+			got, want := parallel.AutocorrectMultiple(word, 5), sequential.AutocorrectMultiple(word, 5)
+			if !slices.EqualFunc(got, want, sameCorrection) {
+				t.Errorf("AutocorrectMultiple(%q) = %v with 4 workers, %v with 1", word, got, want)
+			}
+		}
+	}
+}
+
+// sameCorrection compares corrections allowing for the rounding of confidences summed
+// in a different order
+func sameCorrection(a, b Correction) bool {
+	confidence := a.Confidence - b.Confidence
+	a.Confidence, b.Confidence = 0, 0
+	return a == b && confidence < 1e-9 && confidence > -1e-9
+}
+
+func BenchmarkDocumentScan(b *testing.B) {
+	wt, words := testTrie(b), documentWords()
+	b.ResetTimer()
+	for range b.N {
+		for _, word := range words {
+			scanCandidates(wt, word, DefaultMaxDistance, false)
+		}
+	}
+}
+
+func BenchmarkDocumentSearchTrie(b *testing.B) {
+	wt, words := testTrie(b), documentWords()
+	b.ResetTimer()
+	for range b.N {
+		for _, word := range words {
+			trieCandidates(wt, word, DefaultMaxDistance, false)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package spellcheck
+
+import (
+	"math"
+	"strings"
+)
+
+// minSegmentLogProb is the least average log10 probability the words of a split may
+// have. It keeps out splits into rare fragments, such as "lan gauge" for "langauge".
+const minSegmentLogProb = -4.0
+
+// minShortWordLogProb is the least log10 probability of the one- and two-letter words of
+// a split. Web-derived frequency lists rate stray letters and fragments such as "mo" or
+// "ed" highly, so short words must be genuinely common to take part in a split.
+var minShortWordLogProb = [...]float64{1: -2.5, 2: -3.25}
+
+// Segment splits a run-on word such as "thankyou" into the most probable sequence of
+// two or more dictionary words, treating words as independent draws by frequency. It
+// returns nil when no such split exists or word is a dictionary word itself.
+func (wt *WordTrie) Segment(word string) []string {
+	pieces, _ := wt.segment(strings.ToLower(word))
+	return pieces
+}
+
+// segment returns the best split of word into at least two dictionary words and the
+// frequency the dictionary would give the phrase
+func (wt *WordTrie) segment(word string) ([]string, int) {
+	runes := []rune(word)
+	n := len(runes)
+	if n < 2 || wt.totalFrequency == 0 || wt.IsWord(word) {
+		return nil, 0
+	}
+
+	// best[i] is the highest log10 probability of any split of runes[:i], and back[i]
+	// where its last word starts
+	best := make([]float64, n+1)
+	back := make([]int, n+1)
+	for i := 1; i <= n; i++ {
+		best[i] = math.Inf(-1)
+	}
+	total := float64(wt.totalFrequency)
+	for i := 0; i < n; i++ {
+		if math.IsInf(best[i], -1) {
+			continue
+		}
+		wt.prefixWords(runes[i:], func(length, frequency int) {
+			j := i + length - 1
+			// The whole word is the case segmentation is not for
+			if frequency == 0 || (i == 0 && j == n-1) {
+				return
+			}
+			logProb := math.Log10(float64(frequency) / total)
+			if length < len(minShortWordLogProb) && logProb < minShortWordLogProb[length] {
+				return
+			}
+			if p := best[i] + logProb; p > best[j+1] {
+				best[j+1], back[j+1] = p, i
+			}
+		})
+	}
+	if math.IsInf(best[n], -1) {
+		return nil, 0
+	}
+
+	var pieces []string
+	for end := n; end > 0; end = back[end] {
+		pieces = append(pieces, string(runes[back[end]:end]))
+	}
+	if best[n]/float64(len(pieces)) < minSegmentLogProb {
+		return nil, 0
+	}
+	for i, j := 0, len(pieces)-1; i < j; i, j = i+1, j-1 {
+		pieces[i], pieces[j] = pieces[j], pieces[i]
+	}
+	return pieces, int(total * math.Pow(10, best[n]))
+}
+
+// isPhrase reports whether every space-separated word of phrase is a dictionary word
+func (wt *WordTrie) isPhrase(phrase string) bool {
+	words := strings.Fields(phrase)
+	for _, w := range words {
+		if !wt.IsWord(w) {
+			return false
+		}
+	}
+	return len(words) > 0
+}
+
+// phraseFrequency is the frequency of a word, or for a phrase the frequency its words
+// would have as independent draws
+func (wt *WordTrie) phraseFrequency(phrase string) int {
+	words := strings.Fields(phrase)
+	if len(words) == 1 || wt.totalFrequency == 0 {
+		return wt.GetWordFrequency(phrase)
+	}
+	p := 1.0
+	for _, w := range words {
+		p *= float64(wt.GetWordFrequency(w)) / float64(wt.totalFrequency)
+	}
+	return int(p * float64(wt.totalFrequency))
+}
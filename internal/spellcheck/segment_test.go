@@ -0,0 +1,40 @@
+package spellcheck
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSegment(t *testing.T) {
+	small := NewWordTrie()
+	words := "the,1000\nyou,900\nin,800\na,700\nsome,400\nwhere,300\nlot,200\nthank,100\nmorning,90\nsomewhere,60\n"
+	if _, err := small.LoadWords("small.txt", strings.NewReader(words), false); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		wt   *WordTrie
+		word string
+		want []string
+	}{
+		{small, "thankyou", []string{"thank", "you"}},
+		{small, "Thankyou", []string{"thank", "you"}},
+		{small, "alot", []string{"a", "lot"}},
+		{small, "inthemorning", []string{"in", "the", "morning"}},
+		{small, "somewhere", nil},
+		{small, "thankyoux", nil},
+		{testTrie(t), "alot", []string{"a", "lot"}},
+		{testTrie(t), "inthemorning", []string{"in", "the", "morning"}},
+		// Dictionary words stay whole, including the built-in list's own "thankyou"
+		{testTrie(t), "together", nil},
+		{testTrie(t), "therapist", nil},
+		{testTrie(t), "thankyou", nil},
+		// Rare fragments are no split
+		{testTrie(t), "langauge", nil},
+	}
+	for _, tt := range tests {
+		if got := tt.wt.Segment(tt.word); !slices.Equal(got, tt.want) {
+			t.Errorf("Segment(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
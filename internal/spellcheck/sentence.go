@@ -0,0 +1,111 @@
+package spellcheck
+
+import (
+	"regexp"
+	"sort"
+)
+
+var wordRegex = regexp.MustCompile(`\b[a-zA-Z]+(?:['’][a-zA-Z]+)?\b`)
+
+// Token is a word of a text and its byte span in the text
+type Token struct {
+	Text       string
+	Start, End int
+}
+
+// Tokenize splits text into words, skipping whitespace, digits and punctuation
+func Tokenize(text string) []Token {
+	var tokens []Token
+	for _, loc := range wordRegex.FindAllStringIndex(text, -1) {
+		tokens = append(tokens, Token{Text: text[loc[0]:loc[1]], Start: loc[0], End: loc[1]})
+	}
+	return tokens
+}
+
+// CorrectionKind tells a misspelled word from a valid word that is probably the wrong one
+type CorrectionKind int
+
+const (
+	Misspelling CorrectionKind = iota
+	RealWordError
+	// SplitWord joins several tokens, as in "beau tiful", into one word
+	SplitWord
+)
+
+func (k CorrectionKind) String() string {
+	switch k {
+	case RealWordError:
+		return "real-word error"
+	case SplitWord:
+		return "split word"
+	}
+	return "misspelling"
+}
+
+// WordCorrection is a token of a sentence in need of correction and the word replacing
+// it, if any. For a split word the token spans all of its parts.
+type WordCorrection struct {
+	Token
+	Word  string
+	Found bool
+	Kind  CorrectionKind
+}
+
+// CorrectSentence returns a correction for every misspelled or split word of sentence
+// and, with confusion sets, every real-word error, in order. With a language model, candidates are
+// chosen to fit their neighbours; without one each word is corrected on its own.
+func (wt *WordTrie) CorrectSentence(sentence string) []WordCorrection {
+	merges, tokens := wt.findMerges(sentence, Tokenize(sentence))
+	corrections := append(merges, wt.correctMisspellings(tokens)...)
+	if wt.confusions == nil {
+		sortCorrections(corrections)
+		return corrections
+	}
+
+	words := make([]string, len(tokens))
+	corrected := make(map[int]string, len(corrections))
+	for _, c := range corrections {
+		if c.Found {
+			corrected[c.Start] = c.Word
+		}
+	}
+	for i, token := range tokens {
+		words[i] = token.Text
+		if word, ok := corrected[token.Start]; ok {
+			words[i] = word
+		}
+	}
+
+	// A merged word already replaces its span, so it is not flagged again as a real-word error
+	merged := make(map[int]bool, len(merges))
+	for _, m := range merges {
+		merged[m.Start] = true
+	}
+	for _, finding := range wt.detectRealWords(tokens, words) {
+		if !merged[finding.Start] {
+			corrections = append(corrections, finding)
+		}
+	}
+	sortCorrections(corrections)
+	return corrections
+}
+
+func sortCorrections(corrections []WordCorrection) {
+	sort.SliceStable(corrections, func(i, j int) bool { return corrections[i].Start < corrections[j].Start })
+}
+
+func (wt *WordTrie) correctMisspellings(tokens []Token) []WordCorrection {
+	if wt.lm != nil {
+		return wt.correctInContext(tokens)
+	}
+
+	var corrections []WordCorrection
+	for _, token := range tokens {
+		if wt.IsWord(token.Text) {
+			continue
+		}
+		correction, found := wt.Autocorrect(token.Text)
+		corrections = append(corrections, WordCorrection{Token: token, Word: correction.Word, Found: found})
+	}
+	return corrections
+}
@@ -0,0 +1,62 @@
+package spellcheck
+
+import (
+	"fmt"
+	"spellio/levenshtein"
+	"strings"
+)
+
+// Similarity is a normalized similarity from the levenshtein package that ranking can
+// use as an extra signal next to edit distance and frequency
+type Similarity struct {
+	Name  string
+	Score levenshtein.Similarity
+}
+
+var (
+	NoSimilarity          = Similarity{Name: "none"}
+	JaroSimilarity        = Similarity{Name: "jaro", Score: levenshtein.Jaro}
+	JaroWinklerSimilarity = Similarity{Name: "jaro-winkler", Score: levenshtein.JaroWinkler}
+	JaccardSimilarity     = Similarity{Name: "jaccard", Score: levenshtein.Jaccard}
+	DiceSimilarity        = Similarity{Name: "dice", Score: levenshtein.Dice}
+	LCSSimilarity         = Similarity{Name: "lcs", Score: levenshtein.LCSRatio}
+)
+
+// DefaultSimilarityWeight makes a perfect similarity worth one edit
+const DefaultSimilarityWeight = 1.0
+
+func Similarities() []Similarity {
+	return []Similarity{NoSimilarity, JaroSimilarity, JaroWinklerSimilarity, JaccardSimilarity, DiceSimilarity, LCSSimilarity}
+}
+
+func ParseSimilarity(name string) (Similarity, error) {
+	for _, s := range Similarities() {
+		if strings.EqualFold(name, s.Name) {
+			return s, nil
+		}
+	}
+	return Similarity{}, fmt.Errorf("unknown similarity: %s", name)
+}
+
+// SetSimilarity adds s to the ranking score, weight times its value taken off a
+// candidate's distance; NoSimilarity turns the signal off
+func (wt *WordTrie) SetSimilarity(s Similarity, weight float64) error {
+	if weight < 0 {
+		return fmt.Errorf("similarity weight must not be negative: %g", weight)
+	}
+	wt.similarity, wt.similarityWeight = s, weight
+	wt.cache.clear()
+	return nil
+}
+
+func (wt *WordTrie) Similarity() Similarity {
+	return wt.similarity
+}
+
+// similarityBonus is how much s lowers the ranking score of candidate for word
+func (wt *WordTrie) similarityBonus(word, candidate string) float64 {
+	if wt.similarity.Score == nil {
+		return 0
+	}
+	return wt.similarityWeight * wt.similarity.Score(word, candidate)
+}
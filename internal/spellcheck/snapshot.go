@@ -0,0 +1,394 @@
+package spellcheck
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"time"
+)
+
+// A snapshot holds a WordTrie and the indexes derived from it, serialised so that
+// loading it rebuilds the nodes in one pass instead of inserting every word again.
+// Integers are little-endian or varints:
+//
+//	magic     "SPELLIO\x00"
+//	version   uint32
+//	sections  id byte, length uvarint, data; in any order, unknown ids are skipped
+//	checksum  uint32, CRC-32C of everything before it
+//
+// The trie section is required. The phonetic index is rebuilt if its section is
+// missing, and the symspell index is only stored if it was built.
+const (
+	snapshotMagic = "SPELLIO\x00"
+	// SnapshotVersion changes whenever a section changes incompatibly
+	SnapshotVersion = 2
+)
+
+const (
+	trieSection byte = iota + 1
+	phoneticSection
+	symSpellSection
+)
+
+var (
+	snapshotTable    = crc32.MakeTable(crc32.Castagnoli)
+	errCorruptedData = errors.New("corrupted snapshot data")
+)
+
+// IsCompiled tells whether filename starts like a snapshot written by WriteSnapshot or
+// a DAWG written by WriteDAWG
+func IsCompiled(filename string) (bool, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = file.Close() }()
+	r := bufio.NewReader(file)
+	return hasSnapshotMagic(r) || hasDAWGMagic(r), nil
+}
+
+func hasSnapshotMagic(r *bufio.Reader) bool {
+	magic, _ := r.Peek(len(snapshotMagic))
+	return string(magic) == snapshotMagic
+}
+
+// WriteSnapshot writes the words of wt and its phonetic and symspell indexes to w
+func (wt *WordTrie) WriteSnapshot(w io.Writer) error {
+	out := []byte(snapshotMagic)
+	out = binary.LittleEndian.AppendUint32(out, SnapshotVersion)
+	root, phonetic := wt.Root, wt.phonetic
+	if wt.dawg != nil {
+		t := NewWordTrie()
+		wt.collectWords(t.Insert)
+		root, phonetic = t.Root, t.phonetic
+	}
+	out = appendSection(out, trieSection, encodeTrie(root))
+	out = appendSection(out, phoneticSection, phonetic.encode())
+	if wt.symspell != nil {
+		out = appendSection(out, symSpellSection, wt.symspell.encode())
+	}
+	out = binary.LittleEndian.AppendUint32(out, crc32.Checksum(out, snapshotTable))
+	_, err := w.Write(out)
+	return err
+}
+
+func appendSection(out []byte, id byte, data []byte) []byte {
+	out = append(out, id)
+	out = binary.AppendUvarint(out, uint64(len(data)))
+	return append(out, data...)
+}
+
+// loadSnapshot adds the words of a snapshot to wt. An empty wt takes the snapshot's
+// nodes and indexes as they are; otherwise its words are inserted one by one.
+func (wt *WordTrie) loadSnapshot(name string, data []byte) (DictionaryInfo, error) {
+	start := time.Now()
+	info := DictionaryInfo{Name: name}
+	sections, err := readSections(data)
+	if err != nil {
+		return info, fmt.Errorf("%s: %w", name, err)
+	}
+
+	root, words, total, err := decodeTrie(sections[trieSection])
+	if err != nil {
+		return info, fmt.Errorf("%s: trie: %w", name, err)
+	}
+	info.Words = words
+	if wt.words > 0 {
+		collect(root, func(word string, frequency int) { wt.Insert(word, frequency) })
+		return info, nil
+	}
+
+	phonetic := newPhoneticIndex()
+	if data, ok := sections[phoneticSection]; ok {
+		if phonetic, err = decodePhoneticIndex(data); err != nil {
+			return info, fmt.Errorf("%s: phonetic index: %w", name, err)
+		}
+	} else {
+		collect(root, func(word string, _ int) { phonetic.add(word) })
+	}
+	var symspell *symSpellIndex
+	if data, ok := sections[symSpellSection]; ok {
+		if symspell, err = decodeSymSpellIndex(data); err != nil {
+			return info, fmt.Errorf("%s: symspell index: %w", name, err)
+		}
+		symspell.buildTime = time.Since(start)
+	}
+
+	wt.Root, wt.words, wt.totalFrequency = root, words, total
+	wt.phonetic, wt.symspell, wt.bktree = phonetic, symspell, nil
+	wt.cache.clear()
+	return info, nil
+}
+
+// readSections checks the header and checksum of a snapshot and returns its sections by id
+func readSections(data []byte) (map[byte][]byte, error) {
+	header := len(snapshotMagic) + 4
+	if len(data) < header+4 || string(data[:len(snapshotMagic)]) != snapshotMagic {
+		return nil, errors.New("not a spellio snapshot")
+	}
+	if version := binary.LittleEndian.Uint32(data[len(snapshotMagic):]); version != SnapshotVersion {
+		return nil, fmt.Errorf("snapshot version %d is not supported, recompile it with \"spellio dict compile\"", version)
+	}
+	body, sum := data[:len(data)-4], binary.LittleEndian.Uint32(data[len(data)-4:])
+	if crc32.Checksum(body, snapshotTable) != sum {
+		return nil, errors.New("snapshot checksum mismatch, the file is damaged")
+	}
+
+	sections := make(map[byte][]byte)
+	r := &snapshotReader{data: body[header:]}
+	for len(r.data) > 0 && r.err == nil {
+		id := r.bytes(1)
+		data := r.bytes(r.uvarint())
+		if r.err == nil {
+			sections[id[0]] = data
+		}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	if _, ok := sections[trieSection]; !ok {
+		return nil, errors.New("snapshot has no trie")
+	}
+	return sections, nil
+}
+
+// encodeTrie writes the node count and then every node in preorder as its letter,
+// its child count shifted left by one with the low bit set for a word, and the
+// frequency of a word
+func encodeTrie(root *LetterNode) []byte {
+	count := 0
+	var countNodes func(n *LetterNode)
+	countNodes = func(n *LetterNode) {
+		count++
+		for _, child := range n.Children {
+			countNodes(child)
+		}
+	}
+	countNodes(root)
+
+	out := binary.AppendUvarint(nil, uint64(count))
+	var encode func(n *LetterNode)
+	encode = func(n *LetterNode) {
+		header := uint64(len(n.Children)) << 1
+		if n.IsWord {
+			header |= 1
+		}
+		out = binary.AppendUvarint(out, uint64(n.Letter))
+		out = binary.AppendUvarint(out, header)
+		if n.IsWord {
+			out = binary.AppendVarint(out, int64(n.Frequency))
+		}
+		for _, child := range n.Children {
+			encode(child)
+		}
+	}
+	encode(root)
+	return out
+}
+
+// decodeTrie rebuilds the nodes written by encodeTrie, all in one allocation, and
+// counts the words and their total frequency
+func decodeTrie(data []byte) (root *LetterNode, words, total int, err error) {
+	r := &snapshotReader{data: data}
+	count := r.uvarint()
+	// Every node takes at least two bytes
+	if r.err != nil || count == 0 || count > uint64(len(r.data)/2) {
+		return nil, 0, 0, errCorruptedData
+	}
+	nodes := make([]LetterNode, count)
+	children := make([]*LetterNode, count-1)
+
+	next, nextChild := 0, 0
+	var decode func() *LetterNode
+	decode = func() *LetterNode {
+		if next == len(nodes) {
+			r.err = errCorruptedData
+			return nil
+		}
+		n := &nodes[next]
+		next++
+		n.Letter = rune(r.uvarint())
+		header := r.uvarint()
+		if header&1 == 1 {
+			n.IsWord, n.Frequency = true, int(r.varint())
+			words++
+			total += n.Frequency
+		}
+		k := header >> 1
+		if r.err != nil || k > uint64(len(children)-nextChild) {
+			r.err = errCorruptedData
+			return nil
+		}
+		// Capped so that inserting a child later copies the slice instead of overwriting a neighbour's
+		n.Children = children[nextChild : nextChild+int(k) : nextChild+int(k)]
+		nextChild += int(k)
+		for i := range n.Children {
+			if n.Children[i] = decode(); r.err != nil {
+				return nil
+			}
+		}
+		return n
+	}
+	root = decode()
+	if r.err == nil && (next != len(nodes) || len(r.data) != 0) {
+		r.err = errCorruptedData
+	}
+	if r.err != nil {
+		return nil, 0, 0, r.err
+	}
+	return root, words, total, nil
+}
+
+// collect calls fn for every word under root in alphabetical order
+func collect(root *LetterNode, fn func(string, int)) {
+	(&WordTrie{Root: root}).collectWords(fn)
+}
+
+func (idx *phoneticIndex) encode() []byte {
+	out := binary.AppendUvarint(nil, uint64(len(idx.words)))
+	for _, key := range slices.Sorted(maps.Keys(idx.words)) {
+		out = appendString(out, key)
+		out = binary.AppendUvarint(out, uint64(len(idx.words[key])))
+		for _, word := range idx.words[key] {
+			out = appendString(out, word)
+		}
+	}
+	return out
+}
+
+func decodePhoneticIndex(data []byte) (*phoneticIndex, error) {
+	r := &snapshotReader{data: data}
+	idx := newPhoneticIndex()
+	for keys := r.count(); keys > 0 && r.err == nil; keys-- {
+		key := r.string()
+		words := make([]string, r.count())
+		for i := range words {
+			words[i] = r.string()
+		}
+		idx.words[key] = words
+	}
+	return idx, r.end()
+}
+
+func (idx *symSpellIndex) encode() []byte {
+	out := binary.AppendUvarint(nil, uint64(idx.maxDist))
+	out = binary.AppendUvarint(out, uint64(len(idx.words)))
+	for i, word := range idx.words {
+		out = appendString(out, word)
+		out = binary.AppendVarint(out, int64(idx.freqs[i]))
+	}
+	out = binary.AppendUvarint(out, uint64(len(idx.deletes)))
+	for _, del := range slices.Sorted(maps.Keys(idx.deletes)) {
+		out = appendString(out, del)
+		out = binary.AppendUvarint(out, uint64(len(idx.deletes[del])))
+		for _, id := range idx.deletes[del] {
+			out = binary.AppendUvarint(out, uint64(id))
+		}
+	}
+	return out
+}
+
+func decodeSymSpellIndex(data []byte) (*symSpellIndex, error) {
+	r := &snapshotReader{data: data}
+	idx := &symSpellIndex{maxDist: int(r.uvarint())}
+	n := r.count()
+	idx.words, idx.freqs = make([]string, n), make([]int, n)
+	idx.ids = make(map[string]int32, n)
+	for i := range idx.words {
+		idx.words[i], idx.freqs[i] = r.string(), int(r.varint())
+		idx.ids[idx.words[i]] = int32(i)
+	}
+
+	keys := r.count()
+	idx.deletes = make(map[string][]int32, keys)
+	for ; keys > 0 && r.err == nil; keys-- {
+		del := r.string()
+		ids := make([]int32, r.count())
+		for i := range ids {
+			if ids[i] = int32(r.uvarint()); int(ids[i]) >= n {
+				r.err = errCorruptedData
+			}
+		}
+		idx.deletes[del] = ids
+	}
+	return idx, r.end()
+}
+
+func appendString(out []byte, s string) []byte {
+	out = binary.AppendUvarint(out, uint64(len(s)))
+	return append(out, s...)
+}
+
+// snapshotReader decodes values from data, turning every read after the first error
+// into a no-op so that callers check r.err once
+type snapshotReader struct {
+	data []byte
+	err  error
+}
+
+func (r *snapshotReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	x, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.err = errCorruptedData
+		return 0
+	}
+	r.data = r.data[n:]
+	return x
+}
+
+func (r *snapshotReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	x, n := binary.Varint(r.data)
+	if n <= 0 {
+		r.err = errCorruptedData
+		return 0
+	}
+	r.data = r.data[n:]
+	return x
+}
+
+// count reads a number of items that follow, each at least one byte long
+func (r *snapshotReader) count() int {
+	n := r.uvarint()
+	if n > uint64(len(r.data)) {
+		r.err = errCorruptedData
+		return 0
+	}
+	return int(n)
+}
+
+func (r *snapshotReader) bytes(n uint64) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n > uint64(len(r.data)) {
+		r.err = errCorruptedData
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *snapshotReader) string() string {
+	return string(r.bytes(r.uvarint()))
+}
+
+// end returns the first error, or one if data is left over
+func (r *snapshotReader) end() error {
+	if r.err == nil && len(r.data) != 0 {
+		r.err = errCorruptedData
+	}
+	return r.err
+}
@@ -0,0 +1,131 @@
+package spellcheck
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// smallDictionary is enough words for the indexes to have something to find
+const smallDictionary = "the,1000\nthey,400\nthen,300\nthere,350\nreceive,80\nrecipe,60\nspell,50\n" +
+	"spelling,45\n\"rock 'n' roll\",7\nnaïve,5\nphone,30\nfone,1\nknight,12\nnight,90\n"
+
+// sameDictionary checks that got answers every query of the dictionary as want does
+func sameDictionary(t *testing.T, want, got *WordTrie) {
+	t.Helper()
+	if got.words != want.words || got.totalFrequency != want.totalFrequency {
+		t.Errorf("%d words of total frequency %d, want %d of %d", got.words, got.totalFrequency, want.words, want.totalFrequency)
+	}
+	var wantWords, gotWords []Suggestion
+	want.collectWords(func(word string, frequency int) { wantWords = append(wantWords, Suggestion{word, frequency}) })
+	got.collectWords(func(word string, frequency int) { gotWords = append(gotWords, Suggestion{word, frequency}) })
+	if !slices.Equal(gotWords, wantWords) {
+		t.Errorf("collectWords differs: %d words, want %d", len(gotWords), len(wantWords))
+	}
+
+	words := append(documentWords(), "", "the", "rock 'n' roll", "naïve", "fone", "nite", "zzzz")
+	for _, word := range words {
+		if got, want := got.IsWord(word), want.IsWord(word); got != want {
+			t.Errorf("IsWord(%q) = %v, want %v", word, got, want)
+		}
+		if got, want := got.GetWordFrequency(word), want.GetWordFrequency(word); got != want {
+			t.Errorf("GetWordFrequency(%q) = %d, want %d", word, got, want)
+		}
+		if got, want := got.AutosuggestMultiple(word, 10), want.AutosuggestMultiple(word, 10); !slices.Equal(got, want) {
+			t.Errorf("AutosuggestMultiple(%q) = %v, want %v", word, got, want)
+		}
+		if got, want := got.FindCandidates(word, 2, 10), want.FindCandidates(word, 2, 10); !slices.Equal(got, want) {
+			t.Errorf("FindCandidates(%q) = %v, want %v", word, got, want)
+		}
+		if got, want := got.AutocorrectMultiple(word, 5), want.AutocorrectMultiple(word, 5); !slices.Equal(got, want) {
+			t.Errorf("AutocorrectMultiple(%q) = %v, want %v", word, got, want)
+		}
+		if got, want := got.SoundsLike(word), want.SoundsLike(word); !slices.Equal(got, want) {
+			t.Errorf("SoundsLike(%q) = %v, want %v", word, got, want)
+		}
+	}
+}
+
+func loadSnapshotOf(t *testing.T, wt *WordTrie) *WordTrie {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := wt.WriteSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded := NewWordTrie()
+	info, err := loaded.loadSnapshot("test.snap", buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Words != wt.words {
+		t.Errorf("info.Words = %d, want %d", info.Words, wt.words)
+	}
+	return loaded
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	wt := testTrie(t)
+	sameDictionary(t, wt, loadSnapshotOf(t, wt))
+}
+
+func TestSnapshotRoundTripSymSpell(t *testing.T) {
+	wt := NewWordTrie()
+	if _, err := wt.LoadWords("small.txt", strings.NewReader(smallDictionary), false); err != nil {
+		t.Fatal(err)
+	}
+	if err := wt.UseBackend(SymSpellBackend); err != nil {
+		t.Fatal(err)
+	}
+	loaded := loadSnapshotOf(t, wt)
+	if !reflect.DeepEqual(loaded.symspell.deletes, wt.symspell.deletes) {
+		t.Error("symspell index differs")
+	}
+	if err := loaded.UseBackend(SymSpellBackend); err != nil {
+		t.Fatal(err)
+	}
+	sameDictionary(t, wt, loaded)
+}
+
+func FuzzSnapshotDecoders(f *testing.F) {
+	wt := NewWordTrie()
+	if _, err := wt.LoadWords("small.txt", strings.NewReader(smallDictionary), false); err != nil {
+		f.Fatal(err)
+	}
+	if err := wt.UseBackend(SymSpellBackend); err != nil {
+		f.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := wt.WriteSnapshot(&buf); err != nil {
+		f.Fatal(err)
+	}
+	f.Add(buf.Bytes())
+	sections, err := readSections(buf.Bytes())
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, data := range sections {
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Malformed input must be refused, never panic or hang
+		if sections, err := readSections(data); err == nil {
+			if _, ok := sections[trieSection]; !ok {
+				t.Error("readSections accepted a snapshot without a trie")
+			}
+		}
+		if root, words, _, err := decodeTrie(data); err == nil {
+			count := 0
+			collect(root, func(string, int) { count++ })
+			if count != words {
+				t.Errorf("decodeTrie counted %d words, the trie holds %d", words, count)
+			}
+		}
+		_, _ = decodePhoneticIndex(data)
+		if idx, err := decodeSymSpellIndex(data); err == nil {
+			idx.lookup("the", min(idx.maxDist, 2), OSAMetric, func(string, int, int) {})
+		}
+	})
+}
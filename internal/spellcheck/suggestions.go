@@ -21,38 +21,22 @@ func (wt *WordTrie) Autosuggest(prefix string) (Suggestion, bool) {
 func (wt *WordTrie) AutosuggestMultiple(prefix string, maxSuggestions int) []Suggestion {
 	prefix = strings.ToLower(prefix)
 
-	node := wt.Root
-	for _, ch := range prefix {
-		n, ok := node.Children[ch]
-		if !ok {
-			return nil
-		}
-		node = n
-	}
-
 	var suggestions []Suggestion
-	var dfs func(n *LetterNode, current []rune)
-	dfs = func(n *LetterNode, current []rune) {
-		if n.IsWord {
-			word := string(current)
-			if word != prefix { // Skip the exact prefix match
-				suggestions = append(suggestions, Suggestion{
-					Word:      word,
-					Frequency: wt.GetWordFrequency(word),
-				})
-			}
+	wt.completions(prefix, func(word string, frequency int) {
+		if word != prefix { // Skip the exact prefix match
+			suggestions = append(suggestions, Suggestion{
+				Word:      word,
+				Frequency: frequency,
+			})
 		}
-		for ch, child := range n.Children {
-			dfs(child, append(current, ch))
-		}
-	}
-	dfs(node, []rune(prefix))
+	})
 
 	if len(suggestions) == 0 {
 		return nil
 	}
 
-	sort.Slice(suggestions, func(i, j int) bool {
+	// Words are collected in alphabetical order, which breaks frequency ties
+	sort.SliceStable(suggestions, func(i, j int) bool {
 		return suggestions[i].Frequency > suggestions[j].Frequency
 	})
 
@@ -62,3 +46,15 @@ func (wt *WordTrie) AutosuggestMultiple(prefix string, maxSuggestions int) []Sug
 
 	return suggestions
 }
+
+// completions calls fn for every word starting with prefix, in alphabetical order
+func (wt *WordTrie) completions(prefix string, fn func(string, int)) {
+	node := wt.find(prefix)
+	if wt.dawg != nil {
+		wt.overlay(node, []rune(prefix), func(fn func(string, int)) { wt.dawg.complete(prefix, fn) }, fn)
+		return
+	}
+	if node != nil {
+		collectNode(node, []rune(prefix), fn)
+	}
+}
@@ -0,0 +1,96 @@
+package spellcheck
+
+import "time"
+
+// symSpellIndex maps every string reachable by deleting up to maxDist runes from a
+// dictionary word back to the words that produce it. Two words within edit distance
+// k always share such a delete, even when one of the edits is a transposition, so a
+// lookup only has to probe the deletes of the input.
+type symSpellIndex struct {
+	maxDist   int
+	words     []string
+	freqs     []int
+	ids       map[string]int32
+	deletes   map[string][]int32
+	buildTime time.Duration
+}
+
+func newSymSpellIndex(wt *WordTrie, maxDist int) *symSpellIndex {
+	start := time.Now()
+	idx := &symSpellIndex{
+		maxDist: maxDist,
+		ids:     make(map[string]int32),
+		deletes: make(map[string][]int32),
+	}
+	wt.collectWords(idx.add)
+	idx.buildTime = time.Since(start)
+	return idx
+}
+
+func (idx *symSpellIndex) add(word string, frequency int) {
+	if id, ok := idx.ids[word]; ok {
+		idx.freqs[id] = frequency
+		return
+	}
+
+	id := int32(len(idx.words))
+	idx.words = append(idx.words, word)
+	idx.freqs = append(idx.freqs, frequency)
+	idx.ids[word] = id
+
+	for del := range deletesOf(word, idx.maxDist) {
+		idx.deletes[del] = append(idx.deletes[del], id)
+	}
+}
+
+func (idx *symSpellIndex) lookup(word string, maxDist int, metric Metric, fn func(string, int, int)) {
+	seen := make(map[int32]struct{})
+	for del := range deletesOf(word, maxDist) {
+		for _, id := range idx.deletes[del] {
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+
+			candidate := idx.words[id]
+			dist := metric.Distance(word, candidate, maxDist)
+			if dist <= maxDist {
+				fn(candidate, dist, idx.freqs[id])
+			}
+		}
+	}
+}
+
+func (idx *symSpellIndex) stats() IndexStats {
+	entries := 0
+	for _, ids := range idx.deletes {
+		entries += len(ids)
+	}
+	return IndexStats{
+		Backend:   SymSpellBackend,
+		Words:     len(idx.words),
+		Entries:   entries,
+		BuildTime: idx.buildTime,
+	}
+}
+
+// deletesOf returns word and every distinct string obtained by removing up to maxDist runes from it
+func deletesOf(word string, maxDist int) map[string]struct{} {
+	deletes := map[string]struct{}{word: {}}
+	frontier := []string{word}
+	for d := 0; d < maxDist; d++ {
+		var next []string
+		for _, w := range frontier {
+			runes := []rune(w)
+			for i := range runes {
+				del := string(runes[:i]) + string(runes[i+1:])
+				if _, ok := deletes[del]; !ok {
+					deletes[del] = struct{}{}
+					next = append(next, del)
+				}
+			}
+		}
+		frontier = next
+	}
+	return deletes
+}
@@ -0,0 +1,40 @@
+package spellcheck
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSymSpellMatchesTrie(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the symspell index of the whole dictionary")
+	}
+	trie, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	symspell, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = symspell.UseBackend(SymSpellBackend); err != nil {
+		t.Fatal(err)
+	}
+
+	words := append(documentWords(), "", "a", "teh", "recieve", "naïve", "xyzzy", "thankyou")
+	for _, metric := range Distances() {
+		for _, wt := range []*WordTrie{trie, symspell} {
+			if err = wt.SetDistance(metric); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, word := range words {
+			for maxDist := 0; maxDist <= DefaultMaxDistance; maxDist++ {
+				got, want := symspell.FindCandidates(word, maxDist, 1000), trie.FindCandidates(word, maxDist, 1000)
+				if !slices.Equal(got, want) {
+					t.Errorf("%s: FindCandidates(%q, %d) = %v with symspell, %v with the trie", metric.Name, word, maxDist, got, want)
+				}
+			}
+		}
+	}
+}
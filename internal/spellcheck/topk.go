@@ -0,0 +1,69 @@
+package spellcheck
+
+import "sort"
+
+// topK keeps the k best items pushed to it in a binary heap with the worst of them at
+// the root, so that each push costs O(log k) and the rest are dropped as they arrive.
+// better takes pointers so that comparing large items does not copy them.
+type topK[T any] struct {
+	k      int
+	better func(a, b *T) bool
+	items  []T
+}
+
+func newTopK[T any](k int, better func(a, b *T) bool) *topK[T] {
+	return &topK[T]{k: k, better: better}
+}
+
+// push adds x if fewer than k items are kept or x beats the worst of them
+func (t *topK[T]) push(x T) {
+	switch {
+	case len(t.items) < t.k:
+		t.items = append(t.items, x)
+		t.up(len(t.items) - 1)
+	case t.k > 0 && t.better(&x, &t.items[0]):
+		t.items[0] = x
+		t.down(0)
+	}
+}
+
+// merge pushes the items other keeps
+func (t *topK[T]) merge(other *topK[T]) {
+	for _, x := range other.items {
+		t.push(x)
+	}
+}
+
+// sorted returns the items kept, best first
+func (t *topK[T]) sorted() []T {
+	sort.Slice(t.items, func(i, j int) bool { return t.better(&t.items[i], &t.items[j]) })
+	return t.items
+}
+
+func (t *topK[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !t.better(&t.items[parent], &t.items[i]) {
+			return
+		}
+		t.items[parent], t.items[i] = t.items[i], t.items[parent]
+		i = parent
+	}
+}
+
+func (t *topK[T]) down(i int) {
+	for {
+		worst := i
+		if left := 2*i + 1; left < len(t.items) && t.better(&t.items[worst], &t.items[left]) {
+			worst = left
+		}
+		if right := 2*i + 2; right < len(t.items) && t.better(&t.items[worst], &t.items[right]) {
+			worst = right
+		}
+		if worst == i {
+			return
+		}
+		t.items[i], t.items[worst] = t.items[worst], t.items[i]
+		i = worst
+	}
+}
@@ -1,23 +1,97 @@
 package spellcheck
 
 import (
+	"slices"
+	"spellio/levenshtein"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
+// LetterNode is a node of the trie, reached from its parent by Letter. Children are kept
+// sorted by letter, which is both smaller than a map per node and walks the trie in
+// alphabetical order.
 type LetterNode struct {
-	Children  map[rune]*LetterNode
+	Letter    rune
 	IsWord    bool
 	Frequency int
+	Children  []*LetterNode
+}
+
+// Child returns the child reached by ch, or nil
+func (n *LetterNode) Child(ch rune) *LetterNode {
+	i, found := n.search(ch)
+	if !found {
+		return nil
+	}
+	return n.Children[i]
+}
+
+// child returns the child reached by ch, adding it if it is missing
+func (n *LetterNode) child(ch rune) *LetterNode {
+	i, found := n.search(ch)
+	if !found {
+		n.Children = slices.Insert(n.Children, i, &LetterNode{Letter: ch})
+	}
+	return n.Children[i]
+}
+
+func (n *LetterNode) search(ch rune) (int, bool) {
+	return slices.BinarySearchFunc(n.Children, ch, func(child *LetterNode, ch rune) int {
+		return int(child.Letter - ch)
+	})
 }
 
 type WordTrie struct {
 	Root *LetterNode
+	// dawg, when set, holds the words it was loaded with, and Root is an overlay of those
+	// inserted since, whose frequencies override the DAWG's
+	dawg *DAWG
+
+	backend  Backend
+	symspell *symSpellIndex
+	bktree   *BKTree
+	bkMetric Metric
+	distance Metric
+	costs    levenshtein.CostModel
+	// costUnit is what costs charges for an ordinary edit, see costUnit
+	costUnit int
+
+	// workers is the number of goroutines a trie search may use
+	workers int
+
+	// cache remembers corrections until the dictionary or a search option changes
+	cache *correctionCache
+
+	similarity       Similarity
+	similarityWeight float64
+
+	// phonetic indexes the words of Root that the DAWG, which has an index of its own, lacks
+	phonetic *phoneticIndex
+
+	channel      *ChannelModel
+	channelCosts levenshtein.CostModel
+	lm           *LanguageModel
+	confusions   *ConfusionSets
+
+	// words and totalFrequency estimate word probabilities for the channel model
+	words          int
+	totalFrequency int
 }
 
 func NewWordTrie() *WordTrie {
 	return &WordTrie{
-		Root: &LetterNode{Children: make(map[rune]*LetterNode)},
+		Root:     &LetterNode{},
+		bkMetric: DamerauMetric,
+		distance: OSAMetric,
+		costs:    levenshtein.KeyboardCosts,
+		costUnit: costUnit(levenshtein.KeyboardCosts),
+
+		phonetic: newPhoneticIndex(),
+		cache:    newCorrectionCache(DefaultCacheSize),
+
+		similarity:       NoSimilarity,
+		similarityWeight: DefaultSimilarityWeight,
 	}
 }
 
@@ -25,13 +99,29 @@ func (wt *WordTrie) Insert(word string, frequency int) {
 	word = strings.ToLower(word)
 	n := wt.Root
 	for _, ch := range word {
-		if _, ok := n.Children[ch]; !ok {
-			n.Children[ch] = &LetterNode{Children: make(map[rune]*LetterNode)}
-		}
-		n = n.Children[ch]
+		n = n.child(ch)
+	}
+	previous, found := n.Frequency, n.IsWord
+	if !found && wt.dawg != nil {
+		previous, found = wt.dawg.Lookup(word)
+	}
+	if found {
+		wt.totalFrequency -= previous
+	} else {
+		wt.words++
+		wt.phonetic.add(word)
 	}
 	n.IsWord = true
 	n.Frequency = frequency
+	wt.totalFrequency += frequency
+	wt.cache.clear()
+
+	if wt.symspell != nil {
+		wt.symspell.add(word, frequency)
+	}
+	if wt.bktree != nil {
+		wt.bktree.Add(word, frequency)
+	}
 }
 
 func (wt *WordTrie) IsWord(word string) bool {
@@ -50,44 +140,82 @@ func (wt *WordTrie) IsWord(word string) bool {
 		return wt.IsWord(baseWord)
 	}
 
-	n := wt.Root
-	for _, ch := range word {
-		if _, ok := n.Children[ch]; !ok {
-			return false
-		}
-		n = n.Children[ch]
-	}
-	return n.IsWord
+	_, ok := wt.lookup(word)
+	return ok
 }
 
 func (wt *WordTrie) GetWordFrequency(word string) int {
-	word = strings.ToLower(word)
+	frequency, _ := wt.lookup(strings.ToLower(word))
+	return frequency // 0 if not a valid word
+}
+
+// lookup returns the frequency of word and whether it is in the dictionary
+func (wt *WordTrie) lookup(word string) (int, bool) {
+	if n := wt.find(word); n != nil && n.IsWord {
+		return n.Frequency, true
+	}
+	if wt.dawg != nil {
+		return wt.dawg.Lookup(word)
+	}
+	return 0, false
+}
+
+// find returns the node reached by the letters of word, or nil
+func (wt *WordTrie) find(word string) *LetterNode {
 	n := wt.Root
 	for _, ch := range word {
-		if _, ok := n.Children[ch]; !ok {
-			return 0 // Word not found
+		if n = n.Child(ch); n == nil {
+			return nil
 		}
-		n = n.Children[ch]
-	}
-	if n.IsWord {
-		return n.Frequency
 	}
-	return 0 // Not a valid word
+	return n
 }
 
 func (wt *WordTrie) collectWords(fn func(string, int)) {
-	var dfs func(node *LetterNode, prefix []rune)
-	dfs = func(node *LetterNode, prefix []rune) {
-		if node.IsWord {
-			fn(string(prefix), node.Frequency)
+	if wt.dawg != nil {
+		wt.overlay(wt.Root, nil, func(fn func(string, int)) { wt.dawg.collect(0, 0, nil, fn) }, fn)
+		return
+	}
+	collectNode(wt.Root, nil, fn)
+}
+
+// collectNode calls fn for every word under node, whose letters are prefix, in
+// alphabetical order
+func collectNode(node *LetterNode, prefix []rune, fn func(string, int)) {
+	if node.IsWord {
+		fn(string(prefix), node.Frequency)
+	}
+	for _, child := range node.Children {
+		collectNode(child, append(prefix, child.Letter), fn)
+	}
+}
+
+// prefixWords calls fn with the length and frequency of every word that runes start with
+func (wt *WordTrie) prefixWords(runes []rune, fn func(int, int)) {
+	// overlaid[length] is set for the words of the overlay, which override the DAWG's
+	var overlaid []bool
+	if wt.dawg != nil {
+		overlaid = make([]bool, len(runes)+1)
+	}
+	node := wt.Root
+	for j, ch := range runes {
+		if node = node.Child(ch); node == nil {
+			break
 		}
-		for ch, child := range node.Children {
-			prefix = append(prefix, ch)
-			dfs(child, prefix)
-			prefix = prefix[:len(prefix)-1]
+		if node.IsWord {
+			fn(j+1, node.Frequency)
+			if overlaid != nil {
+				overlaid[j+1] = true
+			}
 		}
 	}
-	dfs(wt.Root, []rune{})
+	if wt.dawg != nil {
+		wt.dawg.prefixWords(runes, func(length, frequency int) {
+			if !overlaid[length] {
+				fn(length, frequency)
+			}
+		})
+	}
 }
 
 func (wt *WordTrie) isPossessive(word string) bool {
@@ -99,7 +227,7 @@ func (wt *WordTrie) preserveCase(original, corrected string) string {
 		return corrected
 	}
 
-	if unicode.IsUpper(rune(original[0])) {
+	if first, _ := utf8.DecodeRuneInString(original); unicode.IsUpper(first) {
 		if len(corrected) > 0 {
 			runes := []rune(corrected)
 			runes[0] = unicode.ToUpper(runes[0])
@@ -0,0 +1,136 @@
+package levenshtein
+
+// Operation is a single step of an alignment between two strings
+type Operation int
+
+const (
+	Match Operation = iota
+	Insert
+	Delete
+	Substitute
+	Transpose
+)
+
+var operationNames = [...]string{
+	Match:      "match",
+	Insert:     "insert",
+	Delete:     "delete",
+	Substitute: "substitute",
+	Transpose:  "transpose",
+}
+
+func (op Operation) String() string {
+	if op >= 0 && int(op) < len(operationNames) {
+		return operationNames[op]
+	}
+	return "unknown"
+}
+
+// Edit is one step in turning a into b. PosA and PosB are the rune offsets in a and b
+// where the step applies; From is the text it consumes from a and To the text it
+// produces in b, so From is empty for an Insert and To is empty for a Delete.
+type Edit struct {
+	Op         Operation
+	PosA, PosB int
+	From, To   string
+	Cost       int
+}
+
+// Align returns a minimal sequence of edits, matches included, that turns a into b
+// under the plain Levenshtein distance
+func Align(a, b string) []Edit {
+	return ModelAlign(a, b, UniformCosts)
+}
+
+func OSAAlign(a, b string) []Edit {
+	return ModelOSAAlign(a, b, UniformCosts)
+}
+
+func KeyboardAwareAlign(a, b string) []Edit {
+	return ModelAlign(a, b, KeyboardCosts)
+}
+
+func KeyboardAwareOSAAlign(a, b string) []Edit {
+	return ModelOSAAlign(a, b, KeyboardCosts)
+}
+
+func ModelAlign(a, b string, model CostModel) []Edit {
+	return modelAlign([]rune(a), []rune(b), model, false)
+}
+
+func ModelOSAAlign(a, b string, model CostModel) []Edit {
+	return modelAlign([]rune(a), []rune(b), model, true)
+}
+
+// modelAlign fills the full cost matrix and traces a cheapest path back from the
+// bottom-right corner, preferring diagonal steps so that matches line up.
+func modelAlign(a, b []rune, model CostModel, transpositions bool) []Edit {
+	la, lb := len(a), len(b)
+	d := make([][]int, la+1)
+	for i := range d {
+		d[i] = make([]int, lb+1)
+	}
+	for i := 1; i <= la; i++ {
+		d[i][0] = d[i-1][0] + model.Deletion(a[i-1])
+	}
+	for j := 1; j <= lb; j++ {
+		d[0][j] = d[0][j-1] + model.Insertion(b[j-1])
+	}
+	for i := 1; i <= la; i++ {
+		for j := 1; j <= lb; j++ {
+			d[i][j] = minimum(
+				d[i-1][j]+model.Deletion(a[i-1]),
+				d[i][j-1]+model.Insertion(b[j-1]),
+				d[i-1][j-1]+model.Substitution(a[i-1], b[j-1]),
+			)
+			if transpositions && canTranspose(a, b, i, j) {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+model.Transposition(a[i-2], a[i-1]))
+			}
+		}
+	}
+
+	var edits []Edit
+	i, j := la, lb
+	for i > 0 || j > 0 {
+		var e Edit
+		pi, pj := i, j
+		switch {
+		case i > 0 && j > 0 && d[i][j] == d[i-1][j-1]+model.Substitution(a[i-1], b[j-1]):
+			pi, pj = i-1, j-1
+			e = Edit{Op: Substitute, From: string(a[pi]), To: string(b[pj])}
+			if a[pi] == b[pj] {
+				e.Op = Match
+			}
+		case transpositions && canTranspose(a, b, i, j) && d[i][j] == d[i-2][j-2]+model.Transposition(a[i-2], a[i-1]):
+			pi, pj = i-2, j-2
+			e = Edit{Op: Transpose, From: string(a[pi:i]), To: string(b[pj:j])}
+		case i > 0 && d[i][j] == d[i-1][j]+model.Deletion(a[i-1]):
+			pi = i - 1
+			e = Edit{Op: Delete, From: string(a[pi])}
+		default:
+			pj = j - 1
+			e = Edit{Op: Insert, To: string(b[pj])}
+		}
+		e.PosA, e.PosB, e.Cost = pi, pj, d[i][j]-d[pi][pj]
+		edits = append(edits, e)
+		i, j = pi, pj
+	}
+
+	for k, l := 0, len(edits)-1; k < l; k, l = k+1, l-1 {
+		edits[k], edits[l] = edits[l], edits[k]
+	}
+	return edits
+}
+
+func canTranspose(a, b []rune, i, j int) bool {
+	return i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] && a[i-1] != a[i-2]
+}
+
+// EditCost sums the cost of every edit in an alignment
+func EditCost(edits []Edit) int {
+	total := 0
+	for _, e := range edits {
+		total += e.Cost
+	}
+	return total
+}
@@ -0,0 +1,55 @@
+package levenshtein
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAlign(t *testing.T) {
+	tests := []struct {
+		name  string
+		align func(a, b string) []Edit
+		a, b  string
+		want  []Edit
+	}{
+		{"Align", Align, "kitten", "sitting", []Edit{
+			{Op: Substitute, PosA: 0, PosB: 0, From: "k", To: "s", Cost: 1},
+			{Op: Match, PosA: 1, PosB: 1, From: "i", To: "i"},
+			{Op: Match, PosA: 2, PosB: 2, From: "t", To: "t"},
+			{Op: Match, PosA: 3, PosB: 3, From: "t", To: "t"},
+			{Op: Substitute, PosA: 4, PosB: 4, From: "e", To: "i", Cost: 1},
+			{Op: Match, PosA: 5, PosB: 5, From: "n", To: "n"},
+			{Op: Insert, PosA: 6, PosB: 6, To: "g", Cost: 1},
+		}},
+		{"Align", Align, "teh", "the", []Edit{
+			{Op: Match, PosA: 0, PosB: 0, From: "t", To: "t"},
+			{Op: Substitute, PosA: 1, PosB: 1, From: "e", To: "h", Cost: 1},
+			{Op: Substitute, PosA: 2, PosB: 2, From: "h", To: "e", Cost: 1},
+		}},
+		{"OSAAlign", OSAAlign, "teh", "the", []Edit{
+			{Op: Match, PosA: 0, PosB: 0, From: "t", To: "t"},
+			{Op: Transpose, PosA: 1, PosB: 1, From: "eh", To: "he", Cost: 1},
+		}},
+		{"OSAAlign", OSAAlign, "spell", "spel", []Edit{
+			{Op: Match, PosA: 0, PosB: 0, From: "s", To: "s"},
+			{Op: Match, PosA: 1, PosB: 1, From: "p", To: "p"},
+			{Op: Match, PosA: 2, PosB: 2, From: "e", To: "e"},
+			{Op: Delete, PosA: 3, PosB: 3, From: "l", Cost: 1},
+			{Op: Match, PosA: 4, PosB: 3, From: "l", To: "l"},
+		}},
+		{"Align", Align, "", "ab", []Edit{
+			{Op: Insert, PosA: 0, PosB: 0, To: "a", Cost: 1},
+			{Op: Insert, PosA: 0, PosB: 1, To: "b", Cost: 1},
+		}},
+		{"Align", Align, "", "", nil},
+	}
+	for _, tt := range tests {
+		got := tt.align(tt.a, tt.b)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s(%q, %q) = %+v, want %+v", tt.name, tt.a, tt.b, got, tt.want)
+		}
+		if cost, want := EditCost(got), Distance(tt.a, tt.b); tt.name == "Align" && cost != want {
+			t.Errorf("EditCost(%s(%q, %q)) = %d, want the distance %d", tt.name, tt.a, tt.b, cost, want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package levenshtein
+
+// Ukkonen's diagonal band: a path that strays more than threshold diagonals from the main
+// one already costs more than threshold, so only the 2*threshold+1 central diagonals of
+// the matrix need to be filled.
+
+// BandedDistanceWithThreshold has the same semantics as DistanceWithThreshold. Without a
+// threshold the band covers the whole matrix.
+func BandedDistanceWithThreshold(a, b string, threshold int) int {
+	var s Scratch
+	return s.BandedDistanceWithThreshold(a, b, threshold)
+}
+
+func banded[E comparable](s *Scratch, a, b []E, threshold int) int {
+	la, lb := len(a), len(b)
+	if la == 0 {
+		return capDistance(lb, threshold)
+	}
+	if lb == 0 {
+		return capDistance(la, threshold)
+	}
+	band := threshold
+	if band < 0 || band > max(la, lb) {
+		band = max(la, lb)
+	}
+	if la-lb > band || lb-la > band {
+		return threshold + 1
+	}
+
+	// Cells outside the band are treated as infinitely far
+	outside := band + 1
+	prev, curr := s.rows(lb + 1)
+	for j := 0; j <= lb; j++ {
+		prev[j] = min(j, outside)
+	}
+	for i := 1; i <= la; i++ {
+		lo, hi := max(1, i-band), min(lb, i+band)
+		curr[lo-1] = outside
+		if lo == 1 {
+			curr[0] = min(i, outside)
+		}
+		minInRow := curr[lo-1]
+		for j := lo; j <= hi; j++ {
+			cost := 0
+			if a[i-1] != b[j-1] {
+				cost = 1
+			}
+			curr[j] = min(
+				prev[j]+1,      // deletion
+				curr[j-1]+1,    // insertion
+				prev[j-1]+cost, // substitution
+				outside,
+			)
+			if curr[j] < minInRow {
+				minInRow = curr[j]
+			}
+		}
+		if hi < lb {
+			curr[hi+1] = outside
+		}
+		if threshold >= 0 && minInRow > threshold {
+			return threshold + 1
+		}
+		prev, curr = curr, prev
+	}
+	return capDistance(prev[lb], threshold)
+}
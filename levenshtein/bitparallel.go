@@ -0,0 +1,108 @@
+package levenshtein
+
+import "unicode/utf8"
+
+// Myers' bit-vector algorithm, in Hyyrö's formulation for global edit distance, encodes a
+// whole DP column as vertical +1/-1 deltas in two machine words. Each character of the
+// text then costs a constant number of word operations instead of a pass over the column,
+// which is why the shorter word has to fit in 64 runes.
+
+func BitParallelDistance(a, b string) int {
+	return BitParallelDistanceWithThreshold(a, b, -1)
+}
+
+// BitParallelDistanceWithThreshold has the same semantics as DistanceWithThreshold. Words
+// longer than 64 runes on both sides fall back to the banded or full matrix.
+func BitParallelDistanceWithThreshold(a, b string, threshold int) int {
+	var s Scratch
+	return s.DistanceWithThreshold(a, b, threshold)
+}
+
+func bitParallel[E byte | rune](s *Scratch, a, b []E, threshold int) int {
+	// The pattern is the shorter word so that it fits in one machine word
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	m, n := len(a), len(b)
+	if m == 0 {
+		return capDistance(n, threshold)
+	}
+	if m > 64 {
+		return banded(s, a, b, threshold)
+	}
+	if threshold >= 0 && n-m > threshold {
+		return threshold + 1
+	}
+
+	// peq[c] has bit i set when a[i] == c
+	for i, c := range a {
+		s.setPeq(rune(c), s.peq(rune(c))|1<<i)
+	}
+
+	last := uint64(1) << (m - 1)
+	pv, mv := ^uint64(0), uint64(0)
+	score := m
+	for j, c := range b {
+		eq := s.peq(rune(c))
+		xv := eq | mv
+		xh := (((eq & pv) + pv) ^ pv) | eq
+		ph := mv | ^(xh | pv)
+		mh := pv & xh
+		if ph&last != 0 {
+			score++
+		} else if mh&last != 0 {
+			score--
+		}
+		// The top row of the matrix grows by one per column, so a +1 delta is shifted in
+		ph = ph<<1 | 1
+		mh <<= 1
+		pv = mh | ^(xv | ph)
+		mv = ph & xv
+
+		// Each remaining column can lower the score by at most one
+		if threshold >= 0 && score-(n-j-1) > threshold {
+			score = threshold + 1
+			break
+		}
+	}
+
+	for _, c := range a {
+		s.setPeq(rune(c), 0)
+	}
+	return capDistance(score, threshold)
+}
+
+func (s *Scratch) peq(c rune) uint64 {
+	if c < utf8.RuneSelf {
+		return s.asciiPeq[c]
+	}
+	return s.peqMap[c]
+}
+
+func (s *Scratch) setPeq(c rune, bits uint64) {
+	if c < utf8.RuneSelf {
+		s.asciiPeq[c] = bits
+		return
+	}
+	if bits == 0 {
+		delete(s.peqMap, c)
+		return
+	}
+	if s.peqMap == nil {
+		s.peqMap = make(map[rune]uint64)
+	}
+	s.peqMap[c] = bits
+}
+
+// capDistance reports a distance above threshold as threshold+1
+func capDistance(dist, threshold int) int {
+	return capScaled(dist, threshold, 1)
+}
+
+// capScaled is capDistance for a distance in units of unit per edit and a threshold in edits
+func capScaled(dist, threshold, unit int) int {
+	if threshold >= 0 && dist > threshold*unit {
+		return (threshold + 1) * unit
+	}
+	return dist
+}
@@ -0,0 +1,137 @@
+package levenshtein
+
+// CostModel prices each edit operation per rune. Costs must be non-negative, and a
+// substitution of a rune by itself is expected to cost 0.
+type CostModel interface {
+	Insertion(r rune) int
+	Deletion(r rune) int
+	Substitution(a, b rune) int
+	Transposition(a, b rune) int
+}
+
+var (
+	// UniformCosts charges 1 for every edit, which reproduces Distance and OSADistance
+	UniformCosts CostModel = uniformCosts{}
+	// KeyboardCosts prices substitutions by distance on a QWERTY keyboard, which
+	// reproduces KeyboardAwareDistance and KeyboardAwareOSADistance
+	KeyboardCosts = QWERTY.Costs()
+)
+
+type uniformCosts struct{}
+
+func (uniformCosts) Insertion(rune) int           { return 1 }
+func (uniformCosts) Deletion(rune) int            { return 1 }
+func (uniformCosts) Substitution(a, b rune) int   { return unitCost(a, b) }
+func (uniformCosts) Transposition(rune, rune) int { return 1 }
+
+// WeightedCosts is a CostModel with default costs per operation that individual runes or
+// rune pairs can override. Pair keys are ordered as {from, to}.
+type WeightedCosts struct {
+	Insert, Delete, Substitute, Transpose int
+
+	Insertions     map[rune]int
+	Deletions      map[rune]int
+	Substitutions  map[[2]rune]int
+	Transpositions map[[2]rune]int
+}
+
+// NewWeightedCosts returns a model charging cost for every edit until overridden
+func NewWeightedCosts(cost int) *WeightedCosts {
+	return &WeightedCosts{
+		Insert:         cost,
+		Delete:         cost,
+		Substitute:     cost,
+		Transpose:      cost,
+		Insertions:     make(map[rune]int),
+		Deletions:      make(map[rune]int),
+		Substitutions:  make(map[[2]rune]int),
+		Transpositions: make(map[[2]rune]int),
+	}
+}
+
+func (w *WeightedCosts) Insertion(r rune) int {
+	if cost, ok := w.Insertions[r]; ok {
+		return cost
+	}
+	return w.Insert
+}
+
+func (w *WeightedCosts) Deletion(r rune) int {
+	if cost, ok := w.Deletions[r]; ok {
+		return cost
+	}
+	return w.Delete
+}
+
+func (w *WeightedCosts) Substitution(a, b rune) int {
+	if a == b {
+		return 0
+	}
+	if cost, ok := w.Substitutions[[2]rune{a, b}]; ok {
+		return cost
+	}
+	return w.Substitute
+}
+
+func (w *WeightedCosts) Transposition(a, b rune) int {
+	if cost, ok := w.Transpositions[[2]rune{a, b}]; ok {
+		return cost
+	}
+	return w.Transpose
+}
+
+func ModelDistance(a, b string, model CostModel) int {
+	return ModelDistanceWithThreshold(a, b, model, -1)
+}
+
+func ModelOSADistance(a, b string, model CostModel) int {
+	return ModelOSADistanceWithThreshold(a, b, model, -1)
+}
+
+// ModelDistanceWithThreshold prices insertions, deletions and substitutions with model.
+// Unlike the keyboard-aware functions, threshold is in the model's cost units.
+func ModelDistanceWithThreshold(a, b string, model CostModel, threshold int) int {
+	return modelDistance([]rune(a), []rune(b), model, threshold, false)
+}
+
+// ModelOSADistanceWithThreshold also allows transposing adjacent runes, priced with
+// model.Transposition(a, b) for turning "ab" into "ba"
+func ModelOSADistanceWithThreshold(a, b string, model CostModel, threshold int) int {
+	return modelDistance([]rune(a), []rune(b), model, threshold, true)
+}
+
+func modelDistance(a, b []rune, model CostModel, threshold int, transpositions bool) int {
+	la, lb := len(a), len(b)
+	prev2 := make([]int, lb+1)
+	prev := make([]int, lb+1)
+	curr := make([]int, lb+1)
+	for j := 1; j <= lb; j++ {
+		prev[j] = prev[j-1] + model.Insertion(b[j-1])
+	}
+	// A transposition reaches two rows back, so both rows must exceed the threshold
+	// before no cell of a later row can come back under it
+	minInPrev := 0
+	for i := 1; i <= la; i++ {
+		curr[0] = prev[0] + model.Deletion(a[i-1])
+		minInRow := curr[0]
+		for j := 1; j <= lb; j++ {
+			curr[j] = minimum(
+				prev[j]+model.Deletion(a[i-1]),               // deletion
+				curr[j-1]+model.Insertion(b[j-1]),            // insertion
+				prev[j-1]+model.Substitution(a[i-1], b[j-1]), // substitution
+			)
+			if transpositions && i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+model.Transposition(a[i-2], a[i-1])) // transposition
+			}
+			if curr[j] < minInRow {
+				minInRow = curr[j]
+			}
+		}
+		if threshold >= 0 && minInRow > threshold && (!transpositions || minInPrev > threshold) {
+			return threshold + 1
+		}
+		minInPrev = minInRow
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return capDistance(prev[lb], threshold)
+}
@@ -0,0 +1,160 @@
+package levenshtein
+
+// Transposition-aware variants of the edit distance. Optimal string alignment (OSA)
+// counts swapping two adjacent characters as a single edit but never edits a substring
+// more than once, so it is not a true metric. Full Damerau-Levenshtein lifts that
+// restriction and satisfies the triangle inequality, at the cost of a full matrix.
+
+func OSADistance(a, b string) int {
+	return OSADistanceWithThreshold(a, b, -1)
+}
+
+func KeyboardAwareOSADistance(a, b string) int {
+	return KeyboardAwareOSADistanceWithThreshold(a, b, -1)
+}
+
+func DamerauDistance(a, b string) int {
+	return DamerauDistanceWithThreshold(a, b, -1)
+}
+
+func KeyboardAwareDamerauDistance(a, b string) int {
+	return KeyboardAwareDamerauDistanceWithThreshold(a, b, -1)
+}
+
+func OSADistanceWithThreshold(a, b string, threshold int) int {
+	if isASCII(a) && isASCII(b) {
+		return osa([]byte(a), []byte(b), threshold, 1, unitCost[byte])
+	}
+	return osa([]rune(a), []rune(b), threshold, 1, unitCost[rune])
+}
+
+func KeyboardAwareOSADistanceWithThreshold(a, b string, threshold int) int {
+	if isASCII(a) && isASCII(b) {
+		return osa([]byte(a), []byte(b), threshold, 10, keyboardCost[byte])
+	}
+	return osa([]rune(a), []rune(b), threshold, 10, keyboardCost[rune])
+}
+
+func DamerauDistanceWithThreshold(a, b string, threshold int) int {
+	if isASCII(a) && isASCII(b) {
+		return damerau([]byte(a), []byte(b), threshold, 1, unitCost[byte])
+	}
+	return damerau([]rune(a), []rune(b), threshold, 1, unitCost[rune])
+}
+
+func KeyboardAwareDamerauDistanceWithThreshold(a, b string, threshold int) int {
+	if isASCII(a) && isASCII(b) {
+		return damerau([]byte(a), []byte(b), threshold, 10, keyboardCost[byte])
+	}
+	return damerau([]rune(a), []rune(b), threshold, 10, keyboardCost[rune])
+}
+
+// osa computes the optimal string alignment distance where insertions, deletions and
+// transpositions cost unit and substitutions cost subCost. The threshold is in edits.
+func osa[E comparable](a, b []E, threshold, unit int, subCost func(a, b E) int) int {
+	la, lb := len(a), len(b)
+	if la == 0 {
+		return capScaled(lb*unit, threshold, unit)
+	}
+	if lb == 0 {
+		return capScaled(la*unit, threshold, unit)
+	}
+	// Three rows are needed since a transposition looks two rows back
+	prev2 := make([]int, lb+1)
+	prev := make([]int, lb+1)
+	curr := make([]int, lb+1)
+	for j := 0; j <= lb; j++ {
+		prev[j] = j * unit
+	}
+	for i := 1; i <= la; i++ {
+		curr[0] = i * unit
+		minInRow := curr[0]
+		for j := 1; j <= lb; j++ {
+			curr[j] = minimum(
+				prev[j]+unit,                      // deletion
+				curr[j-1]+unit,                    // insertion
+				prev[j-1]+subCost(a[i-1], b[j-1]), // substitution
+			)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+unit) // transposition
+			}
+			if curr[j] < minInRow {
+				minInRow = curr[j]
+			}
+		}
+		if threshold >= 0 && minInRow > threshold*unit {
+			return (threshold + 1) * unit
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return capScaled(prev[lb], threshold, unit)
+}
+
+// damerau computes the unrestricted Damerau-Levenshtein distance (Lowrance-Wagner) where
+// a transposition may have insertions and deletions between the swapped characters.
+func damerau[E comparable](a, b []E, threshold, unit int, subCost func(a, b E) int) int {
+	la, lb := len(a), len(b)
+	if la == 0 {
+		return capScaled(lb*unit, threshold, unit)
+	}
+	if lb == 0 {
+		return capScaled(la*unit, threshold, unit)
+	}
+	if threshold >= 0 && (la-lb > threshold || lb-la > threshold) {
+		return (threshold + 1) * unit
+	}
+
+	// d is offset by one in both dimensions so that row and column 0 can hold a sentinel
+	maxDist := (la + lb) * unit
+	d := make([][]int, la+2)
+	for i := range d {
+		d[i] = make([]int, lb+2)
+	}
+	d[0][0] = maxDist
+	for i := 0; i <= la; i++ {
+		d[i+1][0] = maxDist
+		d[i+1][1] = i * unit
+	}
+	for j := 0; j <= lb; j++ {
+		d[0][j+1] = maxDist
+		d[1][j+1] = j * unit
+	}
+
+	// Give every distinct element a dense id so the last-seen table can be a slice
+	ids := make(map[E]int, la+lb)
+	aIDs, bIDs := make([]int, la), make([]int, lb)
+	for i, c := range a {
+		if _, ok := ids[c]; !ok {
+			ids[c] = len(ids)
+		}
+		aIDs[i] = ids[c]
+	}
+	for j, c := range b {
+		if _, ok := ids[c]; !ok {
+			ids[c] = len(ids)
+		}
+		bIDs[j] = ids[c]
+	}
+
+	// lastRow[c] is the last row of a in which the element with id c appeared
+	lastRow := make([]int, len(ids))
+	for i := 1; i <= la; i++ {
+		lastCol := 0
+		for j := 1; j <= lb; j++ {
+			k, l := lastRow[bIDs[j-1]], lastCol
+			cost := subCost(a[i-1], b[j-1])
+			if cost == 0 {
+				lastCol = j
+			}
+			d[i+1][j+1] = min(
+				d[i][j]+cost,   // substitution
+				d[i+1][j]+unit, // insertion
+				d[i][j+1]+unit, // deletion
+				d[k][l]+(i-k-1)*unit+unit+(j-l-1)*unit, // transposition
+			)
+		}
+		lastRow[aIDs[i-1]] = i
+	}
+
+	return capScaled(d[la+1][lb+1], threshold, unit)
+}
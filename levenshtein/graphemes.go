@@ -0,0 +1,85 @@
+package levenshtein
+
+import "unicode"
+
+// Grapheme variants treat each user-perceived character as one unit, so a letter followed
+// by a combining accent ("é") or an emoji sequence counts as a single edit rather
+// than one per rune.
+
+func GraphemeDistance(a, b string) int {
+	return GraphemeDistanceWithThreshold(a, b, -1)
+}
+
+func GraphemeOSADistance(a, b string) int {
+	return GraphemeOSADistanceWithThreshold(a, b, -1)
+}
+
+func GraphemeDamerauDistance(a, b string) int {
+	return GraphemeDamerauDistanceWithThreshold(a, b, -1)
+}
+
+func GraphemeDistanceWithThreshold(a, b string, threshold int) int {
+	return wagnerFischer(Graphemes(a), Graphemes(b), threshold)
+}
+
+func GraphemeOSADistanceWithThreshold(a, b string, threshold int) int {
+	return osa(Graphemes(a), Graphemes(b), threshold, 1, unitCost[string])
+}
+
+func GraphemeDamerauDistanceWithThreshold(a, b string, threshold int) int {
+	return damerau(Graphemes(a), Graphemes(b), threshold, 1, unitCost[string])
+}
+
+const zeroWidthJoiner = '\u200d'
+
+// Graphemes splits s into grapheme clusters. It approximates the extended grapheme
+// cluster rules of UAX #29: combining marks, emoji modifiers, Hangul jamo and anything
+// after a zero width joiner attach to the preceding rune, regional indicators pair up
+// into flags and CR LF stays together.
+func Graphemes(s string) []string {
+	var clusters []string
+	start := 0
+	prev := rune(-1)
+	regionalIndicators := 0
+	for i, r := range s {
+		if i > start && !extendsCluster(prev, r, regionalIndicators) {
+			clusters = append(clusters, s[start:i])
+			start = i
+			regionalIndicators = 0
+		}
+		if isRegionalIndicator(r) {
+			regionalIndicators++
+		} else {
+			regionalIndicators = 0
+		}
+		prev = r
+	}
+	if start < len(s) {
+		clusters = append(clusters, s[start:])
+	}
+	return clusters
+}
+
+// extendsCluster reports whether r belongs to the cluster ending in prev, which itself
+// ends in a run of regionalIndicators regional indicator symbols
+func extendsCluster(prev, r rune, regionalIndicators int) bool {
+	switch {
+	case prev == '\r' && r == '\n':
+		return true
+	case prev == zeroWidthJoiner:
+		return true
+	case r == zeroWidthJoiner, unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc):
+		return true
+	case r >= 0x1F3FB && r <= 0x1F3FF: // emoji skin tone modifiers
+		return true
+	case r >= 0x1160 && r <= 0x11FF: // Hangul medial vowels and final consonants
+		return true
+	case isRegionalIndicator(prev) && isRegionalIndicator(r):
+		return regionalIndicators%2 == 1
+	}
+	return false
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
@@ -0,0 +1,164 @@
+package levenshtein
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// Layout places every key of a keyboard on a grid measured in key widths, so the cost of
+// substituting one character for another can follow the physical distance between keys.
+// Shifted characters share the position of their key.
+type Layout struct {
+	Name string
+	keys map[rune]keyPosition
+
+	// asciiCosts caches SubstitutionCost for ASCII pairs, the hot path of every keyboard-aware distance
+	asciiCosts [utf8.RuneSelf][utf8.RuneSelf]int8
+}
+
+type keyPosition struct {
+	x, y float64
+}
+
+// LayoutRow describes one physical row of keys. Offset is the horizontal position of the
+// row's first key; a space in Shifted marks a key without a shifted character.
+type LayoutRow struct {
+	Offset  float64
+	Keys    string
+	Shifted string
+}
+
+// Row offsets of a standard staggered keyboard, starting at the key left of "1". ISO
+// bottom rows that begin with an extra key left of the first letter start at isoBottom.
+const (
+	numberRow = 0
+	topRow    = 1.5
+	homeRow   = 1.75
+	bottomRow = 2.25
+	isoBottom = 1.25
+)
+
+var (
+	QWERTY = NewLayout("qwerty",
+		LayoutRow{numberRow, "`1234567890-=", "~!@#$%^&*()_+"},
+		LayoutRow{topRow, "qwertyuiop[]\\", "QWERTYUIOP{}|"},
+		LayoutRow{homeRow, "asdfghjkl;'", "ASDFGHJKL:\""},
+		LayoutRow{bottomRow, "zxcvbnm,./", "ZXCVBNM<>?"},
+	)
+	Dvorak = NewLayout("dvorak",
+		LayoutRow{numberRow, "`1234567890[]", "~!@#$%^&*(){}"},
+		LayoutRow{topRow, "',.pyfgcrl/=\\", "\"<>PYFGCRL?+|"},
+		LayoutRow{homeRow, "aoeuidhtns-", "AOEUIDHTNS_"},
+		LayoutRow{bottomRow, ";qjkxbmwvz", ":QJKXBMWVZ"},
+	)
+	Colemak = NewLayout("colemak",
+		LayoutRow{numberRow, "`1234567890-=", "~!@#$%^&*()_+"},
+		LayoutRow{topRow, "qwfpgjluy;[]\\", "QWFPGJLUY:{}|"},
+		LayoutRow{homeRow, "arstdhneio'", "ARSTDHNEIO\""},
+		LayoutRow{bottomRow, "zxcvbkm,./", "ZXCVBKM<>?"},
+	)
+	AZERTY = NewLayout("azerty",
+		LayoutRow{numberRow, "²&é\"'(-è_çà)=", " 1234567890°+"},
+		LayoutRow{topRow, "azertyuiop^$", "AZERTYUIOP¨£"},
+		LayoutRow{homeRow, "qsdfghjklmù*", "QSDFGHJKLM%µ"},
+		LayoutRow{isoBottom, "<wxcvbn,;:!", ">WXCVBN?./§"},
+	)
+	QWERTZ = NewLayout("qwertz",
+		LayoutRow{numberRow, "^1234567890ß´", "°!\"§$%&/()=?`"},
+		LayoutRow{topRow, "qwertzuiopü+", "QWERTZUIOPÜ*"},
+		LayoutRow{homeRow, "asdfghjklöä#", "ASDFGHJKLÖÄ'"},
+		LayoutRow{isoBottom, "<yxcvbnm,.-", ">YXCVBNM;:_"},
+	)
+)
+
+// Layouts lists the built-in layouts
+func Layouts() []*Layout {
+	return []*Layout{QWERTY, Dvorak, Colemak, AZERTY, QWERTZ}
+}
+
+func ParseLayout(name string) (*Layout, error) {
+	for _, l := range Layouts() {
+		if strings.EqualFold(name, l.Name) {
+			return l, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown keyboard layout: %s", name)
+}
+
+// NewLayout builds a layout from its rows, listed top to bottom one key width apart
+func NewLayout(name string, rows ...LayoutRow) *Layout {
+	l := &Layout{Name: name, keys: make(map[rune]keyPosition)}
+	for y, row := range rows {
+		shifted := []rune(row.Shifted)
+		for x, r := range []rune(row.Keys) {
+			pos := keyPosition{x: row.Offset + float64(x), y: float64(y)}
+			l.keys[r] = pos
+			if x < len(shifted) && shifted[x] != ' ' {
+				l.keys[shifted[x]] = pos
+			}
+		}
+	}
+	for a := range rune(utf8.RuneSelf) {
+		for b := range rune(utf8.RuneSelf) {
+			l.asciiCosts[a][b] = int8(l.substitutionCost(a, b))
+		}
+	}
+	return l
+}
+
+// KeyDistance returns the distance between the keys typing a and b in key widths, and
+// false if either character is not on the layout
+func (l *Layout) KeyDistance(a, b rune) (float64, bool) {
+	pa, okA := l.keys[a]
+	pb, okB := l.keys[b]
+	if !okA || !okB {
+		return 0, false
+	}
+	return math.Hypot(pa.x-pb.x, pa.y-pb.y), true
+}
+
+// SubstitutionCost prices replacing a with b on a scale where any other edit costs 10:
+// 8 for the other character on the same key, 9 for a neighbouring key and 10 from two
+// keys away or for characters the layout does not have
+func (l *Layout) SubstitutionCost(a, b rune) int {
+	if a < utf8.RuneSelf && b < utf8.RuneSelf && a >= 0 && b >= 0 {
+		return int(l.asciiCosts[a][b])
+	}
+	return l.substitutionCost(a, b)
+}
+
+func (l *Layout) substitutionCost(a, b rune) int {
+	if a == b {
+		return 0
+	}
+	d, ok := l.KeyDistance(a, b)
+	if !ok {
+		return 10
+	}
+	return min(10, 8+int(math.Round(d)))
+}
+
+// DistanceWithThreshold is KeyboardAwareDistanceWithThreshold with substitutions priced
+// by SubstitutionCost, and capped the same way at threshold+1 edits
+func (l *Layout) DistanceWithThreshold(a, b string, threshold int) int {
+	if isASCII(a) && isASCII(b) {
+		return weightedWagnerFischer([]byte(a), []byte(b), threshold, 10, func(x, y byte) int { return int(l.asciiCosts[x][y]) })
+	}
+	return weightedWagnerFischer([]rune(a), []rune(b), threshold, 10, l.SubstitutionCost)
+}
+
+// Costs returns the CostModel charging 10 per edit and SubstitutionCost for substitutions
+func (l *Layout) Costs() CostModel {
+	return layoutCosts{l}
+}
+
+type layoutCosts struct {
+	layout *Layout
+}
+
+func (layoutCosts) Insertion(rune) int           { return 10 }
+func (layoutCosts) Deletion(rune) int            { return 10 }
+func (c layoutCosts) Substitution(a, b rune) int { return c.layout.SubstitutionCost(a, b) }
+func (layoutCosts) Transposition(rune, rune) int { return 10 }
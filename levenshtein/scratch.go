@@ -0,0 +1,58 @@
+package levenshtein
+
+import "unicode/utf8"
+
+// Scratch holds the buffers the distance functions need, so that bulk callers can reuse
+// one Scratch across calls instead of allocating per call. The zero value is ready to
+// use; a Scratch must not be shared between goroutines.
+type Scratch struct {
+	bytesA, bytesB []byte
+	runesA, runesB []rune
+	prev, curr     []int
+
+	asciiPeq [utf8.RuneSelf]uint64
+	peqMap   map[rune]uint64
+}
+
+// DistanceWithThreshold picks the fastest exact algorithm for the input: bit-parallel
+// when either word fits in 64 runes, the diagonal band when a threshold is given and
+// the full Wagner-Fischer matrix otherwise.
+func (s *Scratch) DistanceWithThreshold(a, b string, threshold int) int {
+	if utf8.RuneCountInString(a) <= 64 || utf8.RuneCountInString(b) <= 64 {
+		return s.BitParallelDistanceWithThreshold(a, b, threshold)
+	}
+	return s.BandedDistanceWithThreshold(a, b, threshold)
+}
+
+func (s *Scratch) BitParallelDistanceWithThreshold(a, b string, threshold int) int {
+	if isASCII(a) && isASCII(b) {
+		s.bytesA, s.bytesB = append(s.bytesA[:0], a...), append(s.bytesB[:0], b...)
+		return bitParallel(s, s.bytesA, s.bytesB, threshold)
+	}
+	s.runesA, s.runesB = appendRunes(s.runesA[:0], a), appendRunes(s.runesB[:0], b)
+	return bitParallel(s, s.runesA, s.runesB, threshold)
+}
+
+func (s *Scratch) BandedDistanceWithThreshold(a, b string, threshold int) int {
+	if isASCII(a) && isASCII(b) {
+		s.bytesA, s.bytesB = append(s.bytesA[:0], a...), append(s.bytesB[:0], b...)
+		return banded(s, s.bytesA, s.bytesB, threshold)
+	}
+	s.runesA, s.runesB = appendRunes(s.runesA[:0], a), appendRunes(s.runesB[:0], b)
+	return banded(s, s.runesA, s.runesB, threshold)
+}
+
+// rows returns two DP rows of length n, growing the buffers if needed
+func (s *Scratch) rows(n int) ([]int, []int) {
+	if cap(s.prev) < n {
+		s.prev, s.curr = make([]int, n), make([]int, n)
+	}
+	return s.prev[:n], s.curr[:n]
+}
+
+func appendRunes(dst []rune, s string) []rune {
+	for _, r := range s {
+		dst = append(dst, r)
+	}
+	return dst
+}
@@ -0,0 +1,171 @@
+package levenshtein
+
+// Similarity scores how alike two strings are, from 0 for nothing in common to 1 for
+// identical strings. Two empty strings are identical. All similarities compare runes.
+type Similarity func(a, b string) float64
+
+// JaroWinklerPrefixScale and JaroWinklerPrefixLength control how much JaroWinkler
+// rewards a common prefix: each of up to 4 shared leading runes closes another tenth of
+// the gap to 1.
+const (
+	JaroWinklerPrefixScale  = 0.1
+	JaroWinklerPrefixLength = 4
+	// jaroWinklerBoostThreshold is Winkler's cutoff below which the prefix is not rewarded
+	jaroWinklerBoostThreshold = 0.7
+)
+
+// Jaro counts runes that match within half the longer length of each other and the
+// transpositions among them
+func Jaro(a, b string) float64 {
+	return jaro([]rune(a), []rune(b))
+}
+
+// JaroWinkler is Jaro with a bonus for a common prefix, which suits names and other
+// words whose beginning is usually typed right
+func JaroWinkler(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	sim := jaro(ra, rb)
+	if sim < jaroWinklerBoostThreshold {
+		return sim
+	}
+	prefix := 0
+	for prefix < min(len(ra), len(rb), JaroWinklerPrefixLength) && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+	return sim + float64(prefix)*JaroWinklerPrefixScale*(1-sim)
+}
+
+func jaro(a, b []rune) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	window := max(len(a), len(b))/2 - 1
+	window = max(window, 0)
+	matchedA := make([]bool, len(a))
+	matchedB := make([]bool, len(b))
+	matches := 0
+	for i, r := range a {
+		for j := max(0, i-window); j <= min(len(b)-1, i+window); j++ {
+			if !matchedB[j] && b[j] == r {
+				matchedA[i], matchedB[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	// Matched runes that appear in a different order are transposed, counted in halves
+	halfTranspositions := 0
+	j := 0
+	for i, r := range a {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if r != b[j] {
+			halfTranspositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	t := float64(halfTranspositions / 2)
+	return (m/float64(len(a)) + m/float64(len(b)) + (m-t)/m) / 3
+}
+
+// Jaccard compares the sets of bigrams of both strings: shared bigrams over all bigrams
+func Jaccard(a, b string) float64 {
+	return NGramJaccard(2)(a, b)
+}
+
+// Dice compares the sets of bigrams of both strings: twice the shared bigrams over the
+// bigrams of each, which weighs shared bigrams more than Jaccard
+func Dice(a, b string) float64 {
+	return NGramDice(2)(a, b)
+}
+
+// NGramJaccard returns Jaccard over runs of n runes. A string shorter than n is a single
+// n-gram of its own.
+func NGramJaccard(n int) Similarity {
+	return func(a, b string) float64 {
+		shared, sizeA, sizeB := compareNGrams(a, b, n)
+		if sizeA+sizeB == 0 {
+			return 1
+		}
+		return float64(shared) / float64(sizeA+sizeB-shared)
+	}
+}
+
+// NGramDice returns Dice over runs of n runes. A string shorter than n is a single
+// n-gram of its own.
+func NGramDice(n int) Similarity {
+	return func(a, b string) float64 {
+		shared, sizeA, sizeB := compareNGrams(a, b, n)
+		if sizeA+sizeB == 0 {
+			return 1
+		}
+		return 2 * float64(shared) / float64(sizeA+sizeB)
+	}
+}
+
+// compareNGrams returns the number of distinct n-grams the strings share and how many
+// distinct n-grams each has
+func compareNGrams(a, b string, n int) (shared, sizeA, sizeB int) {
+	gramsA, gramsB := nGrams([]rune(a), n), nGrams([]rune(b), n)
+	for g := range gramsA {
+		if gramsB[g] {
+			shared++
+		}
+	}
+	return shared, len(gramsA), len(gramsB)
+}
+
+func nGrams(r []rune, n int) map[string]bool {
+	grams := make(map[string]bool)
+	if len(r) == 0 {
+		return grams
+	}
+	if len(r) < n {
+		grams[string(r)] = true
+		return grams
+	}
+	for i := 0; i+n <= len(r); i++ {
+		grams[string(r[i:i+n])] = true
+	}
+	return grams
+}
+
+// LCSRatio is twice the length of the longest common subsequence over the combined
+// length of both strings, the ratio Python's difflib reports
+func LCSRatio(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra)+len(rb) == 0 {
+		return 1
+	}
+	return 2 * float64(lcsLength(ra, rb)) / float64(len(ra)+len(rb))
+}
+
+func lcsLength(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				curr[j] = prev[j-1] + 1
+			} else {
+				curr[j] = max(prev[j], curr[j-1])
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,32 @@
+package levenshtein
+
+import (
+	"math"
+	"testing"
+)
+
+func TestJaroWinkler(t *testing.T) {
+	tests := []struct {
+		a, b              string
+		jaro, jaroWinkler float64
+	}{
+		{"MARTHA", "MARHTA", 0.944, 0.961},
+		{"DIXON", "DICKSONX", 0.767, 0.813},
+		{"DWAYNE", "DUANE", 0.822, 0.840},
+		{"abc", "abc", 1, 1},
+		{"", "", 1, 1},
+		{"abc", "", 0, 0},
+		{"abc", "xyz", 0, 0},
+	}
+	for _, tt := range tests {
+		if got := Jaro(tt.a, tt.b); math.Abs(got-tt.jaro) > 0.0005 {
+			t.Errorf("Jaro(%q, %q) = %.4f, want %.3f", tt.a, tt.b, got, tt.jaro)
+		}
+		if got := JaroWinkler(tt.a, tt.b); math.Abs(got-tt.jaroWinkler) > 0.0005 {
+			t.Errorf("JaroWinkler(%q, %q) = %.4f, want %.3f", tt.a, tt.b, got, tt.jaroWinkler)
+		}
+		if got, want := Jaro(tt.b, tt.a), Jaro(tt.a, tt.b); got != want {
+			t.Errorf("Jaro(%q, %q) = %.4f, not symmetric with %.4f", tt.b, tt.a, got, want)
+		}
+	}
+}
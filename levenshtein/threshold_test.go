@@ -0,0 +1,117 @@
+package levenshtein
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// thresholdPairs returns edge cases and random words over a small alphabet, so that
+// distances stay small enough for thresholds to matter, with some longer than 64 runes
+func thresholdPairs() [][2]string {
+	pairs := [][2]string{
+		{"", ""}, {"", "a"}, {"abc", ""}, {"", "héllo"}, {"kitten", "sitting"},
+		{"teh", "the"}, {"ca", "abc"}, {"naïve", "naive"}, {"日本語", "日本"},
+		{strings.Repeat("ab", 40), strings.Repeat("ba", 40)},
+		{strings.Repeat("x", 70), strings.Repeat("x", 68) + "yz"},
+	}
+	rng := rand.New(rand.NewSource(1))
+	word := func(n int) string {
+		const alphabet = "abcdé"
+		runes := []rune(alphabet)
+		w := make([]rune, n)
+		for i := range w {
+			w[i] = runes[rng.Intn(len(runes))]
+		}
+		return string(w)
+	}
+	for range 300 {
+		pairs = append(pairs, [2]string{word(rng.Intn(8)), word(rng.Intn(8))})
+	}
+	for range 20 {
+		pairs = append(pairs, [2]string{word(60 + rng.Intn(10)), word(60 + rng.Intn(10))})
+	}
+	return pairs
+}
+
+// TestThresholdVariantsAgree checks every thresholded implementation of a distance
+// against the full distance capped at threshold+1
+func TestThresholdVariantsAgree(t *testing.T) {
+	var scratch Scratch
+	type variant struct {
+		name     string
+		distance func(a, b string, threshold int) int
+	}
+	uniformModel := func(a, b string, threshold int) int {
+		return ModelDistanceWithThreshold(a, b, UniformCosts, threshold)
+	}
+	uniformModelOSA := func(a, b string, threshold int) int {
+		return ModelOSADistanceWithThreshold(a, b, UniformCosts, threshold)
+	}
+	dvorak := func(a, b string) int {
+		return ModelDistance(a, b, Dvorak.Costs())
+	}
+	tests := []struct {
+		full     func(a, b string) int
+		unit     int
+		variants []variant
+	}{
+		{Distance, 1, []variant{
+			{"DistanceWithThreshold", DistanceWithThreshold},
+			{"BitParallelDistanceWithThreshold", BitParallelDistanceWithThreshold},
+			{"BandedDistanceWithThreshold", BandedDistanceWithThreshold},
+			{"Scratch.DistanceWithThreshold", scratch.DistanceWithThreshold},
+			{"Scratch.BitParallelDistanceWithThreshold", scratch.BitParallelDistanceWithThreshold},
+			{"Scratch.BandedDistanceWithThreshold", scratch.BandedDistanceWithThreshold},
+			{"ModelDistanceWithThreshold(UniformCosts)", uniformModel},
+		}},
+		{OSADistance, 1, []variant{
+			{"OSADistanceWithThreshold", OSADistanceWithThreshold},
+			{"ModelOSADistanceWithThreshold(UniformCosts)", uniformModelOSA},
+		}},
+		{DamerauDistance, 1, []variant{{"DamerauDistanceWithThreshold", DamerauDistanceWithThreshold}}},
+		{KeyboardAwareDistance, 10, []variant{{"KeyboardAwareDistanceWithThreshold", KeyboardAwareDistanceWithThreshold}}},
+		{dvorak, 10, []variant{{"Dvorak.DistanceWithThreshold", Dvorak.DistanceWithThreshold}}},
+		{KeyboardAwareOSADistance, 10, []variant{{"KeyboardAwareOSADistanceWithThreshold", KeyboardAwareOSADistanceWithThreshold}}},
+		{KeyboardAwareDamerauDistance, 10, []variant{{"KeyboardAwareDamerauDistanceWithThreshold", KeyboardAwareDamerauDistanceWithThreshold}}},
+		{GraphemeDistance, 1, []variant{{"GraphemeDistanceWithThreshold", GraphemeDistanceWithThreshold}}},
+		{GraphemeOSADistance, 1, []variant{{"GraphemeOSADistanceWithThreshold", GraphemeOSADistanceWithThreshold}}},
+		{GraphemeDamerauDistance, 1, []variant{{"GraphemeDamerauDistanceWithThreshold", GraphemeDamerauDistanceWithThreshold}}},
+	}
+
+	for _, pair := range thresholdPairs() {
+		a, b := pair[0], pair[1]
+		// The plain distance of precomposed text is the same whichever way it is measured
+		if d, want := Distance(a, b), BitParallelDistance(a, b); d != want {
+			t.Errorf("Distance(%q, %q) = %d, BitParallelDistance = %d", a, b, d, want)
+		}
+		for _, tt := range tests {
+			full := tt.full(a, b)
+			for threshold := -1; threshold <= 4; threshold++ {
+				want := capScaled(full, threshold, tt.unit)
+				for _, v := range tt.variants {
+					if got := v.distance(a, b, threshold); got != want {
+						t.Errorf("%s(%q, %q, %d) = %d, want %d", v.name, a, b, threshold, got, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestEmptyInputIsCapped(t *testing.T) {
+	for _, distance := range []func(a, b string, threshold int) int{
+		DistanceWithThreshold, BitParallelDistanceWithThreshold, BandedDistanceWithThreshold,
+		OSADistanceWithThreshold, DamerauDistanceWithThreshold, GraphemeDistanceWithThreshold,
+	} {
+		if got := distance("", "hello", 2); got != 3 {
+			t.Errorf("distance(\"\", \"hello\", 2) = %d, want 3", got)
+		}
+		if got := distance("hello", "", 2); got != 3 {
+			t.Errorf("distance(\"hello\", \"\", 2) = %d, want 3", got)
+		}
+	}
+	if got := KeyboardAwareDistanceWithThreshold("", "hello", 2); got != 30 {
+		t.Errorf("KeyboardAwareDistanceWithThreshold(\"\", \"hello\", 2) = %d, want 30", got)
+	}
+}
@@ -2,53 +2,14 @@
 // This implementation uses dynamic programming to compute the Levenshtein distance
 // between two strings, which is the minimum number of single-character edits (insertions,
 // deletions, or substitutions) required to change one string into the other.
+// Strings are compared rune by rune, or by grapheme cluster with the Grapheme variants.
 package levenshtein
 
-import "slices"
-
-var keyboardLayout = map[rune][]rune{
-	'q':  {'w', 'a', 's'},
-	'w':  {'q', 'e', 'a', 's', 'd'},
-	'e':  {'w', 'r', 's', 'd', 'f'},
-	'r':  {'e', 't', 'd', 'f', 'g'},
-	't':  {'r', 'y', 'f', 'g', 'h'},
-	'y':  {'t', 'u', 'g', 'h', 'j'},
-	'u':  {'y', 'i', 'h', 'j', 'k'},
-	'i':  {'u', 'o', 'j', 'k', 'l'},
-	'o':  {'i', 'p', 'k', 'l'},
-	'p':  {'o', 'l'},
-	'a':  {'q', 'w', 's', 'z'},
-	's':  {'a', 'w', 'e', 'd', 'z', 'x'},
-	'd':  {'s', 'e', 'r', 'f', 'x', 'c'},
-	'f':  {'d', 'r', 't', 'g', 'c', 'v'},
-	'g':  {'f', 't', 'y', 'h', 'v', 'b'},
-	'h':  {'g', 'y', 'u', 'j', 'b', 'n'},
-	'j':  {'h', 'u', 'i', 'k', 'n', 'm'},
-	'k':  {'j', 'i', 'o', 'l', 'm'},
-	'l':  {'k', 'o', 'p', 'm', '\''},
-	'\'': {'l', ';'},
-	';':  {'l', '\'', 'p'},
-	'z':  {'a', 's', 'x'},
-	'x':  {'z', 's', 'd', 'c'},
-	'c':  {'x', 'd', 'f', 'v'},
-	'v':  {'c', 'f', 'g', 'b'},
-	'b':  {'v', 'g', 'h', 'n'},
-	'n':  {'b', 'h', 'j', 'm'},
-	'm':  {'n', 'j', 'k', 'l'},
-}
+import "unicode/utf8"
 
+// keyboardDistance prices substitutions for the KeyboardAware functions on a QWERTY keyboard
 func keyboardDistance(a, b rune) int {
-	if a == b {
-		return 0
-	}
-
-	if adjacent, exists := keyboardLayout[a]; exists {
-		if slices.Contains(adjacent, b) {
-			return 9
-		}
-	}
-
-	return 10
+	return QWERTY.SubstitutionCost(a, b)
 }
 
 func Distance(a, b string) int {
@@ -59,13 +20,31 @@ func KeyboardAwareDistance(a, b string) int {
 	return KeyboardAwareDistanceWithThreshold(a, b, -1)
 }
 
+// DistanceWithThreshold measures in runes. ASCII input, by far the common case, is
+// compared byte by byte without decoding. With a threshold of 0 or more, any distance
+// above it is reported as threshold+1; every WithThreshold function caps the same way,
+// in its own units for the weighted ones. A negative threshold measures in full.
 func DistanceWithThreshold(a, b string, threshold int) int {
+	if isASCII(a) && isASCII(b) {
+		return wagnerFischer([]byte(a), []byte(b), threshold)
+	}
+	return wagnerFischer([]rune(a), []rune(b), threshold)
+}
+
+func KeyboardAwareDistanceWithThreshold(a, b string, threshold int) int {
+	if isASCII(a) && isASCII(b) {
+		return weightedWagnerFischer([]byte(a), []byte(b), threshold, 10, keyboardCost[byte])
+	}
+	return weightedWagnerFischer([]rune(a), []rune(b), threshold, 10, keyboardCost[rune])
+}
+
+func wagnerFischer[E comparable](a, b []E, threshold int) int {
 	la, lb := len(a), len(b)
 	if la == 0 {
-		return lb
+		return capDistance(lb, threshold)
 	}
 	if lb == 0 {
-		return la
+		return capDistance(la, threshold)
 	}
 	// Allocate 2 rows instead of the full matrix for optimization
 	prev := make([]int, lb+1)
@@ -95,43 +74,64 @@ func DistanceWithThreshold(a, b string, threshold int) int {
 		}
 		prev, curr = curr, prev
 	}
-	return prev[lb]
+	return capDistance(prev[lb], threshold)
 }
 
-func KeyboardAwareDistanceWithThreshold(a, b string, threshold int) int {
+// weightedWagnerFischer charges unit for insertions and deletions and subCost for
+// substitutions. The threshold is in edits, so it is scaled by unit.
+func weightedWagnerFischer[E comparable](a, b []E, threshold, unit int, subCost func(a, b E) int) int {
 	la, lb := len(a), len(b)
 	if la == 0 {
-		return lb * 10
+		return capScaled(lb*unit, threshold, unit)
 	}
 	if lb == 0 {
-		return la * 10
+		return capScaled(la*unit, threshold, unit)
 	}
 
 	prev := make([]int, lb+1)
 	curr := make([]int, lb+1)
 	for j := 0; j <= lb; j++ {
-		prev[j] = j * 10
+		prev[j] = j * unit
 	}
 	for i := 1; i <= la; i++ {
-		curr[0] = i * 10
+		curr[0] = i * unit
 		minInRow := curr[0]
 		for j := 1; j <= lb; j++ {
-			cost := keyboardDistance(rune(a[i-1]), rune(b[j-1]))
 			curr[j] = minimum(
-				prev[j]+10,     // deletion
-				curr[j-1]+10,   // insertion
-				prev[j-1]+cost, // substitution
+				prev[j]+unit,                      // deletion
+				curr[j-1]+unit,                    // insertion
+				prev[j-1]+subCost(a[i-1], b[j-1]), // substitution
 			)
 			if curr[j] < minInRow {
 				minInRow = curr[j]
 			}
 		}
-		if threshold >= 0 && minInRow > threshold*10 {
-			return (threshold + 1) * 10
+		if threshold >= 0 && minInRow > threshold*unit {
+			return (threshold + 1) * unit
 		}
 		prev, curr = curr, prev
 	}
-	return prev[lb]
+	return capScaled(prev[lb], threshold, unit)
+}
+
+func unitCost[E comparable](a, b E) int {
+	if a == b {
+		return 0
+	}
+	return 1
+}
+
+func keyboardCost[E byte | rune](a, b E) int {
+	return keyboardDistance(rune(a), rune(b))
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
 }
 
 func minimum(a, b, c int) int {
@@ -6,22 +6,104 @@ import (
 	"os"
 	"spellio/internal/command"
 	"spellio/internal/spellcheck"
+	"spellio/levenshtein"
 
 	"github.com/urfave/cli/v2"
 )
 
 const version = "1.0.0"
 
-func main() {
-	wt, err := spellcheck.New()
-	if err != nil {
-		log.Fatalf("failed to load word-trie: %v", err)
+var (
+	editsFlag = &cli.BoolFlag{
+		Name:  "edits",
+		Usage: "show the letters each correction changes",
+	}
+	modelFlag = &cli.StringFlag{
+		Name:  "model",
+		Usage: "rank corrections with a noisy-channel model written by train",
 	}
+)
+
+func main() {
+	// Dictionaries are loaded by Configure once the flags are known
+	wt := spellcheck.NewWordTrie()
 
 	app := &cli.App{
 		Name:    "spellio",
 		Usage:   "A spell checker and text correction tool",
 		Version: version,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "backend",
+				Usage: "candidate search backend (trie, symspell, bktree)",
+				Value: spellcheck.TrieBackend.String(),
+			},
+			&cli.IntFlag{
+				Name:  "workers",
+				Usage: "goroutines searching the trie backend at once, 0 for one per CPU",
+				Value: 1,
+			},
+			&cli.IntFlag{
+				Name:  "cache-size",
+				Usage: "number of lookups to remember the corrections of, 0 to turn the cache off",
+				Value: spellcheck.DefaultCacheSize,
+			},
+			&cli.StringFlag{
+				Name:  "bk-metric",
+				Usage: "metric the bktree backend is keyed on (levenshtein, damerau, keyboard)",
+				Value: spellcheck.DamerauMetric.Name,
+			},
+			&cli.StringFlag{
+				Name:  "distance",
+				Usage: "edit distance candidates are measured with (levenshtein, osa)",
+				Value: spellcheck.OSAMetric.Name,
+			},
+			&cli.StringFlag{
+				Name:  "costs",
+				Usage: "edit cost model candidates are ranked by (uniform, keyboard, or a cost file path or file:path)",
+				Value: "keyboard",
+			},
+			&cli.StringFlag{
+				Name:  "layout",
+				Usage: "keyboard layout for keyboard-aware costs (qwerty, dvorak, colemak, azerty, qwertz)",
+				Value: levenshtein.QWERTY.Name,
+			},
+			&cli.StringFlag{
+				Name:  "similarity",
+				Usage: "extra ranking signal (none, jaro, jaro-winkler, jaccard, dice, lcs)",
+				Value: spellcheck.NoSimilarity.Name,
+			},
+			&cli.Float64Flag{
+				Name:  "similarity-weight",
+				Usage: "number of edits a perfect similarity is worth in ranking",
+				Value: spellcheck.DefaultSimilarityWeight,
+			},
+			&cli.StringFlag{
+				Name:  "lm",
+				Usage: "n-gram count file for choosing sentence corrections that fit their context",
+			},
+			&cli.BoolFlag{
+				Name:  "real-words",
+				Usage: "flag valid words that are probably confused with another, like \"too\" for \"to\"",
+			},
+			&cli.StringFlag{
+				Name:  "confusions",
+				Usage: "confusion set file to use instead of the built-in sets",
+			},
+			&cli.StringSliceFlag{
+				Name:  "dict",
+				Usage: "dictionary file of word,frequency lines stacked on the built-in one, repeatable (also $SPELLIO_DICT)",
+			},
+			&cli.BoolFlag{
+				Name:  "lenient",
+				Usage: "skip malformed dictionary lines with a warning instead of failing",
+			},
+			&cli.StringFlag{
+				Name:  "config",
+				Usage: "config file setting defaults for these options (default: $XDG_CONFIG_HOME/spellio/config)",
+			},
+		},
+		Before: command.Configure(wt),
 		Action: func(c *cli.Context) error {
 			// If arguments were provided but no valid subcommand matched, show help
 			if c.NArg() > 0 {
@@ -48,6 +130,7 @@ func main() {
 				Name:      "correct",
 				Usage:     "Suggest corrections for a misspelled word",
 				ArgsUsage: "<word>",
+				Flags:     []cli.Flag{editsFlag, modelFlag},
 				Action:    command.CorrectCommand(wt),
 			},
 			{
@@ -55,8 +138,65 @@ func main() {
 				Aliases:   []string{"s"},
 				Usage:     "Check and correct all words in a sentence",
 				ArgsUsage: "<sentence>",
+				Flags:     []cli.Flag{editsFlag, modelFlag},
 				Action:    command.SentenceCommand(wt),
 			},
+			{
+				Name:      "soundslike",
+				Usage:     "List dictionary words that sound like a word",
+				ArgsUsage: "<word>",
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "limit",
+						Usage: "maximum number of words to list, 0 for all",
+						Value: 10,
+					},
+				},
+				Action: command.SoundsLikeCommand(wt),
+			},
+			{
+				Name:      "train",
+				Usage:     "Train a noisy-channel model from misspelling and correction pairs",
+				ArgsUsage: "<pairs-file>...",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "write the model to this file instead of standard output",
+					},
+				},
+				Action: command.TrainCommand(),
+			},
+			{
+				Name:  "dict",
+				Usage: "Manage dictionaries",
+				Subcommands: []*cli.Command{
+					{
+						Name:  "compile",
+						Usage: "Write the loaded dictionaries and search index to a snapshot or DAWG that loads faster",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "output",
+								Aliases:  []string{"o"},
+								Usage:    "file to write, to be passed to --dict or put in a data directory",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "format",
+								Usage: "snapshot, or dawg for a smaller read-only dictionary shared between processes",
+								Value: "snapshot",
+							},
+						},
+						Action: command.DictCompileCommand(wt),
+					},
+				},
+			},
+			{
+				Name:      "stats",
+				Usage:     "Report build time, memory and lookup latency of each backend",
+				ArgsUsage: "[word...]",
+				Action:    command.StatsCommand(wt),
+			},
 			{
 				Name:    "interactive",
 				Aliases: []string{"i"},
@@ -66,7 +206,7 @@ func main() {
 		},
 	}
 
-	if err = app.Run(os.Args); err != nil {
+	if err := app.Run(os.Args); err != nil {
 		log.Fatal(err)
 	}
 }
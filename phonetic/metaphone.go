@@ -0,0 +1,202 @@
+// Package phonetic encodes words by how they sound, so that misspellings such as
+// "fonetik" or "nollij" share a key with the words they were meant to be.
+package phonetic
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Metaphone returns the Metaphone key of word: consonant sounds written as letters, with
+// "0" for "th" and "X" for "sh". Vowels only count at the start of a word, accented
+// letters are read without their accents and anything other than a letter is ignored;
+// a word with letters outside the Latin alphabet has no key. Besides Lawrence Philips'
+// original rules it drops the silent first letter of "ps" like Double Metaphone, so
+// "psychology" and "sikology" match.
+func Metaphone(word string) string {
+	w, ok := letters(word)
+	if !ok || len(w) == 0 {
+		return ""
+	}
+
+	switch {
+	case hasPrefix(w, "AE", "GN", "KN", "PN", "PS", "WR"):
+		w = w[1:]
+	case w[0] == 'X':
+		w[0] = 'S'
+	case hasPrefix(w, "WH"):
+		w = append(w[:1], w[2:]...)
+	}
+
+	var key strings.Builder
+	at := func(i int) byte {
+		if i < 0 || i >= len(w) {
+			return 0
+		}
+		return w[i]
+	}
+	for i := 0; i < len(w); i++ {
+		c := w[i]
+		// Doubled letters sound once, except "cc" as in "accent"
+		if c != 'C' && at(i-1) == c {
+			continue
+		}
+		next := at(i + 1)
+
+		switch c {
+		case 'A', 'E', 'I', 'O', 'U':
+			if i == 0 {
+				key.WriteByte(c)
+			}
+		case 'B':
+			// Silent in a final "mb", as in "dumb"
+			if !(at(i-1) == 'M' && i == len(w)-1) {
+				key.WriteByte('B')
+			}
+		case 'C':
+			switch {
+			case next == 'I' && at(i+2) == 'A':
+				key.WriteByte('X')
+			case next == 'H':
+				switch {
+				case at(i-1) == 'S', at(i-2) == 'S' && at(i-1) == 'Y', i == 0 && at(i+2) == 'R':
+					// "sch" as in "school", "sych" as in "psychology", "chr" as in "chrome"
+					key.WriteByte('K')
+				default:
+					key.WriteByte('X')
+				}
+				i++
+			case next == 'I' || next == 'E' || next == 'Y':
+				if at(i-1) != 'S' {
+					key.WriteByte('S')
+				}
+			default:
+				key.WriteByte('K')
+			}
+		case 'D':
+			if next == 'G' && isFrontVowel(at(i+2)) {
+				key.WriteByte('J')
+				i++
+			} else {
+				key.WriteByte('T')
+			}
+		case 'G':
+			switch {
+			case next == 'H' && i+2 < len(w) && !isVowel(at(i+2)):
+				// Silent as in "night"
+			case next == 'N' && (i+2 == len(w) || string(w[i+2:]) == "ED"):
+				// Silent as in "sign" and "signed"
+			case isFrontVowel(next) && at(i-1) != 'G':
+				key.WriteByte('J')
+			default:
+				key.WriteByte('K')
+			}
+		case 'H':
+			switch {
+			case i > 0 && strings.IndexByte("CSPTG", at(i-1)) >= 0:
+			case isVowel(at(i-1)) && !isVowel(next):
+			default:
+				key.WriteByte('H')
+			}
+		case 'K':
+			if at(i-1) != 'C' {
+				key.WriteByte('K')
+			}
+		case 'P':
+			if next == 'H' {
+				key.WriteByte('F')
+			} else {
+				key.WriteByte('P')
+			}
+		case 'Q':
+			key.WriteByte('K')
+		case 'S':
+			switch {
+			case next == 'H':
+				key.WriteByte('X')
+				i++
+			case next == 'I' && (at(i+2) == 'O' || at(i+2) == 'A'):
+				key.WriteByte('X')
+			default:
+				key.WriteByte('S')
+			}
+		case 'T':
+			switch {
+			case next == 'I' && (at(i+2) == 'O' || at(i+2) == 'A'):
+				key.WriteByte('X')
+			case next == 'H':
+				key.WriteByte('0')
+				i++
+			case next == 'C' && at(i+2) == 'H':
+				// Silent as in "watch"
+			default:
+				key.WriteByte('T')
+			}
+		case 'V':
+			key.WriteByte('F')
+		case 'W', 'Y':
+			if isVowel(next) {
+				key.WriteByte(c)
+			}
+		case 'X':
+			key.WriteString("KS")
+		case 'Z':
+			key.WriteByte('S')
+		default:
+			key.WriteByte(c)
+		}
+	}
+	return key.String()
+}
+
+// letters returns the ASCII letters of word in upper case
+// folds spells accented Latin letters with the letters a to z they are read as
+var folds = func() map[rune]string {
+	folds := map[rune]string{'ß': "SS", 'æ': "AE", 'œ': "OE", 'þ': "TH", 'ð': "TH"}
+	for base, accented := range map[string]string{
+		"A": "àáâãäåāăą", "C": "çćĉċč", "D": "ďđ", "E": "èéêëēĕėęě", "G": "ĝğġģ",
+		"H": "ĥħ", "I": "ìíîïĩīĭįı", "J": "ĵ", "K": "ķ", "L": "ĺļľŀł", "N": "ñńņň",
+		"O": "òóôõöøōŏő", "R": "ŕŗř", "S": "śŝşšș", "T": "ţťŧț", "U": "ùúûüũūŭůűų",
+		"W": "ŵ", "Y": "ýÿŷ", "Z": "źżž",
+	} {
+		for _, r := range accented {
+			folds[r] = base
+		}
+	}
+	return folds
+}()
+
+// letters returns the letters of word in upper case, with accents folded away, and
+// false if word has a letter that cannot be folded to the letters a to z
+func letters(word string) ([]byte, bool) {
+	w := make([]byte, 0, len(word))
+	for _, r := range word {
+		r = unicode.ToLower(r)
+		switch {
+		case 'a' <= r && r <= 'z':
+			w = append(w, byte(r-'a'+'A'))
+		case folds[r] != "":
+			w = append(w, folds[r]...)
+		case unicode.IsLetter(r):
+			return nil, false
+		}
+	}
+	return w, true
+}
+
+func hasPrefix(w []byte, prefixes ...string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(string(w), p) {
+			return true
+		}
+	}
+	return false
+}
+
+func isVowel(c byte) bool {
+	return c == 'A' || c == 'E' || c == 'I' || c == 'O' || c == 'U'
+}
+
+func isFrontVowel(c byte) bool {
+	return c == 'E' || c == 'I' || c == 'Y'
+}
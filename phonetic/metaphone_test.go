@@ -0,0 +1,47 @@
+package phonetic
+
+import "testing"
+
+func TestMetaphone(t *testing.T) {
+	tests := []struct {
+		word, want string
+	}{
+		{"phonetic", "FNTK"},
+		{"thumb", "0M"},
+		{"school", "SKL"},
+		{"knight", "NT"},
+		{"wright", "RT"},
+		{"dumb", "TM"},
+		{"Ääkkönen", "AKNN"},
+		{"Straße", "STRS"},
+		{"rock 'n' roll", "RKNRL"},
+		{"Ελλάδα", ""},
+		{"東京", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Metaphone(tt.word); got != tt.want {
+			t.Errorf("Metaphone(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestMetaphoneSoundAlikes(t *testing.T) {
+	tests := []struct {
+		misspelling, word string
+	}{
+		{"fonetik", "phonetic"},
+		{"nollij", "knowledge"},
+		{"sikology", "psychology"},
+		{"naïve", "naive"},
+		{"café", "cafe"},
+	}
+	for _, tt := range tests {
+		if got, want := Metaphone(tt.misspelling), Metaphone(tt.word); got != want || got == "" {
+			t.Errorf("Metaphone(%q) = %q, Metaphone(%q) = %q, want them equal", tt.misspelling, got, tt.word, want)
+		}
+	}
+	if got := Metaphone("Ääkkönen"); got == Metaphone("canon") {
+		t.Errorf("Metaphone(Ääkkönen) = %q, the key of canon", got)
+	}
+}
@@ -0,0 +1,9 @@
+// Package resources holds the data files compiled into the spellio binary.
+package resources
+
+import _ "embed"
+
+// EnglishWords is the default dictionary, one "word,frequency" line per word
+//
+//go:embed english_words_freqs.txt
+var EnglishWords []byte